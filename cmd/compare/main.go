@@ -0,0 +1,113 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	flags "github.com/jessevdk/go-flags"
+	log "github.com/sirupsen/logrus"
+	"github.com/xidongc/mongo_ebenchmark/pkg/report"
+	"os"
+	"text/tabwriter"
+)
+
+// Compare options, thresholds are in percent and 0 disables check
+type Options struct {
+	Method    string  `long:"method" description:"only gate on given rpc method, eg: mprpc.MongoProxy.Find"`
+	Namespace string  `long:"namespace" description:"only gate on given namespace, eg: sku"`
+	P50       float64 `long:"p50" description:"max p50 latency regression in percent"`
+	P90       float64 `long:"p90" description:"max p90 latency regression in percent"`
+	P99       float64 `long:"p99" description:"max p99 latency regression in percent"`
+	Rps       float64 `long:"rps" description:"max rps drop in percent"`
+	ErrorRate float64 `long:"error-rate" description:"max error rate increase in percentage points"`
+	Args      struct {
+		Baseline   string   `positional-arg-name:"baseline" description:"baseline report file or dir"`
+		Candidates []string `positional-arg-name:"candidate" description:"candidate report files or dirs"`
+	} `positional-args:"yes" required:"yes"`
+}
+
+// compare loads two or more reports, prints deltas against the first one
+// and exits with 1 once any threshold is exceeded or a gated key has no
+// delta to compare, eg:
+//
+//     go run cmd/compare/main.go --method mprpc.MongoProxy.Find --namespace sku --p99 5 results/v1 results/v2
+//
+func main() {
+	var options Options
+
+	parser := flags.NewParser(&options, flags.Default)
+	if _, err := parser.Parse(); err != nil {
+		os.Exit(2)
+	}
+	if len(options.Args.Candidates) == 0 {
+		log.Error("at least two reports are required")
+		os.Exit(2)
+	}
+
+	base, err := report.LoadRecords(options.Args.Baseline)
+	if err != nil {
+		log.Errorf("load baseline error with: %s", err)
+		os.Exit(2)
+	}
+	thresholds := report.Thresholds{
+		P50:       options.P50,
+		P90:       options.P90,
+		P99:       options.P99,
+		Rps:       options.Rps,
+		ErrorRate: options.ErrorRate,
+	}
+	gate := report.Gate{Method: options.Method, Namespace: options.Namespace}
+
+	var violations []string
+	for _, path := range options.Args.Candidates {
+		candidate, err := report.LoadRecords(path)
+		if err != nil {
+			log.Errorf("load candidate error with: %s", err)
+			os.Exit(2)
+		}
+		comparison := report.Compare(base, candidate)
+
+		fmt.Printf("%s -> %s\n", options.Args.Baseline, path)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "METHOD\tNAMESPACE\tP50\tP90\tP99\tRPS\tERROR RATE")
+		for _, d := range comparison.Deltas {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s (%+.2f%%)\t%s (%+.2f%%)\t%s (%+.2f%%)\t%.2f (%+.2f%%)\t%.2f%% (%+.2fpp)\n",
+				d.Method, d.Namespace,
+				d.Candidate.P50, d.P50(),
+				d.Candidate.P90, d.P90(),
+				d.Candidate.P99, d.P99(),
+				d.Candidate.Rps, d.Rps(),
+				d.Candidate.ErrorRate*100, d.ErrorRate())
+		}
+		for _, key := range comparison.BaseOnly {
+			_, _ = fmt.Fprintf(w, "%s\t%s\tmissing in candidate\n", key.Method, key.Namespace)
+		}
+		for _, key := range comparison.CandidateOnly {
+			_, _ = fmt.Fprintf(w, "%s\t%s\tmissing in baseline\n", key.Method, key.Namespace)
+		}
+		_ = w.Flush()
+		violations = append(violations, gate.Violations(comparison, thresholds)...)
+		fmt.Println()
+	}
+
+	if len(violations) > 0 {
+		for _, v := range violations {
+			log.Error(v)
+		}
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,109 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package main
+
+import (
+	"context"
+	flags "github.com/jessevdk/go-flags"
+	log "github.com/sirupsen/logrus"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"github.com/xidongc/mongo_ebenchmark/pkg/datagen"
+	"github.com/xidongc/mongo_ebenchmark/pkg/proxy"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Data generator options
+type Options struct {
+	cfg.ProxyConfig
+	datagen.Options
+	Kinds     []string `long:"kind" choice:"product" choice:"sku" choice:"user" choice:"order" description:"kinds of documents to load, all kinds if not specified"`
+	LoadBatch int      `long:"load-batch" default:"1000" description:"documents per insert"`
+	Workers   int      `long:"workers" default:"4" description:"concurrent inserts per kind"`
+}
+
+// datagen seeds product, sku, user and order collections with generated
+// documents through storage, eg:
+//
+//     go run cmd/datagen/main.go --seed 7 --products 1000000 --users 500000 --orders 5000000
+//
+func main() {
+	var options Options
+
+	parser := flags.NewParser(&options, flags.Default)
+	if _, err := parser.Parse(); err != nil {
+		os.Exit(1)
+	}
+
+	generator, err := datagen.NewGenerator(&options.Options)
+	if err != nil {
+		log.Fatal(err)
+	}
+	kinds := datagen.Kinds
+	if len(options.Kinds) > 0 {
+		kinds = nil
+		for _, kind := range options.Kinds {
+			kinds = append(kinds, datagen.Kind(kind))
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		<-sigs
+		cancel()
+	}()
+
+	manager, err := proxy.NewManager(&options.ProxyConfig, cancel)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		if err := manager.Close(); err != nil {
+			log.Error(err)
+		}
+	}()
+
+	storages := make(map[datagen.Kind]proxy.Storage)
+	for _, kind := range kinds {
+		storage, err := manager.Storage(string(kind))
+		if err != nil {
+			log.Fatalf("%s: create storage error with: %s", kind, err)
+		}
+		storages[kind] = storage
+	}
+
+	loader := &datagen.Loader{
+		Generator: generator,
+		Storages:  storages,
+		BatchSize: options.LoadBatch,
+		Workers:   options.Workers,
+	}
+	start := time.Now()
+	loaded, err := loader.Load(ctx, kinds...)
+	for _, kind := range kinds {
+		log.Infof("%s: %d documents loaded", kind, loaded[kind])
+	}
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	log.Infof("data set loaded in %s", time.Since(start))
+}
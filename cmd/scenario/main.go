@@ -0,0 +1,93 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package main
+
+import (
+	"context"
+	flags "github.com/jessevdk/go-flags"
+	log "github.com/sirupsen/logrus"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"github.com/xidongc/mongo_ebenchmark/pkg/scenario"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Scenario runner options
+type Options struct {
+	File       string `short:"f" long:"file" required:"yes" description:"scenario file in yaml or json"`
+	ServerAddr string `long:"server-addr" default:"127.0.0.1:50053" description:"api server address, overridden by target in scenario"`
+	Setup      bool   `long:"setup" description:"create data set before running, same as setup in scenario"`
+	TLS        bool   `long:"tls" description:"connect api server with tls"`
+	TLSCA      string `long:"tls-ca" description:"ca verifying api server certificate, system roots if not specified"`
+	TLSCert    string `long:"tls-cert" description:"client certificate presented to api server for mTLS"`
+	TLSKey     string `long:"tls-key" description:"client private key presented to api server for mTLS"`
+	ServerName string `long:"tls-server-name" description:"server name verified in api server certificate"`
+}
+
+// scenario runs a workload file against api server started by cmd/server.go, eg:
+//
+//     go run cmd/scenario/main.go -f scenarios/browse-and-buy.yaml
+//
+func main() {
+	var options Options
+
+	parser := flags.NewParser(&options, flags.Default)
+	if _, err := parser.Parse(); err != nil {
+		os.Exit(1)
+	}
+
+	s, err := scenario.Load(options.File)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if s.Target == "" {
+		s.Target = options.ServerAddr
+	}
+	s.Setup = s.Setup || options.Setup
+	log.Infof("%+v", s)
+
+	dialOption := grpc.WithInsecure()
+	if options.TLS {
+		tlsConfig, err := cfg.NewClientTLS(options.TLSCA, options.TLSCert, options.TLSKey, options.ServerName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		dialOption = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+	}
+	conn, err := grpc.Dial(s.Target, dialOption)
+	if err != nil {
+		log.Fatalf("connect to api server error: %s", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		<-sigs
+		cancel()
+	}()
+
+	stats, err := scenario.NewRunner(s, conn).Run(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	_ = stats.Print(os.Stdout)
+}
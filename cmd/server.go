@@ -18,13 +18,16 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	flags "github.com/jessevdk/go-flags"
 	log "github.com/sirupsen/logrus"
 	"github.com/xidongc/mongo_ebenchmark/model/order/orderpb"
 	order "github.com/xidongc/mongo_ebenchmark/model/order/service"
 	"github.com/xidongc/mongo_ebenchmark/model/payment/paymentpb"
 	payment "github.com/xidongc/mongo_ebenchmark/model/payment/service"
+	"github.com/xidongc/mongo_ebenchmark/model/payment/service/provider"
 	"github.com/xidongc/mongo_ebenchmark/model/product/productpb"
 	product "github.com/xidongc/mongo_ebenchmark/model/product/service"
 	sku "github.com/xidongc/mongo_ebenchmark/model/sku/service"
@@ -33,22 +36,61 @@ import (
 	"github.com/xidongc/mongo_ebenchmark/model/user/userpb"
 	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
 	server "github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"github.com/xidongc/mongo_ebenchmark/pkg/health"
+	"github.com/xidongc/mongo_ebenchmark/pkg/idempotency"
+	"github.com/xidongc/mongo_ebenchmark/pkg/metrics"
+	"github.com/xidongc/mongo_ebenchmark/pkg/middleware"
+	"github.com/xidongc/mongo_ebenchmark/pkg/proxy"
+	"github.com/xidongc/mongo_ebenchmark/pkg/tracing"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 )
 
+// Api server options
+type Options struct {
+	server.Config
+	provider.MockOptions
+}
+
 func main() {
-	var config server.Config
+	var options Options
 
-	parser := flags.NewParser(&config, flags.Default)
+	parser := flags.NewParser(&options, flags.Default)
 	if _, err := parser.Parse(); err != nil {
 		os.Exit(1)
 	}
-	log.Infof("%+v", config)
+	config := options.Config
+	log.Infof("%+v", options)
+
+	mock, err := provider.NewMock(options.MockOptions)
+	if err != nil {
+		log.Fatal(err)
+	}
+	providers, err := payment.NewRegistry(&config.PaymentConfig, &provider.AliPay{}, mock)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	tracerProvider, err := tracing.New(&config.TraceConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if tracerProvider != nil {
+		tracing.SetProvider(tracerProvider)
+		defer func() {
+			if err := tracerProvider.Shutdown(context.Background()); err != nil {
+				log.Error(err)
+			}
+		}()
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	sigs := make(chan os.Signal, 1)
@@ -60,59 +102,120 @@ func main() {
 	maxSendMsgSizeOpt := grpc.MaxSendMsgSize(maxSendMsgSize)
 	maxRecvMsgSizeOpt := grpc.MaxRecvMsgSize(maxRecvMsgSize)
 
-	svr := grpc.NewServer(maxSendMsgSizeOpt, maxRecvMsgSizeOpt)
-	proxyConfig := &cfg.ProxyConfig{
-		ProxyAddr:    config.ProxyAddr,
-		ProxyPort:    config.ProxyPort,
-		Secure:       config.Secure,
-		RpcTimeout:   config.RpcTimeout,
-		BatchSize:    config.BatchSize,
-		ReadPref:     config.ReadPref,
-		AllowPartial: config.AllowPartial,
+	chain, err := middleware.New(&config.MiddlewareConfig, time.Duration(config.RpcTimeout)*time.Millisecond)
+	if err != nil {
+		log.Fatal(err)
 	}
-	storageClient := *sku.NewClient(proxyConfig, cancel)
+	log.Infof("api server middlewares: %s", strings.Join(chain.Names, ", "))
 
-	if config.Turbo {
-		storageClient.Turbo = true
+	serverOpts := append([]grpc.ServerOption{
+		maxSendMsgSizeOpt,
+		maxRecvMsgSizeOpt,
+	}, chain.ServerOptions()...)
+	serverTLS, err := config.ServerTLS()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if serverTLS != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(serverTLS)))
+	}
+	svr := grpc.NewServer(serverOpts...)
+	proxyConfig := &cfg.ProxyConfig{
+		ProxyAddr:          config.ProxyAddr,
+		ProxyPort:          config.ProxyPort,
+		Secure:             config.Secure,
+		ProxyCA:            config.ProxyCA,
+		ProxyCert:          config.ProxyCert,
+		ProxyKey:           config.ProxyKey,
+		ProxyServerName:    config.ProxyServerName,
+		ProxyPoolSize:      config.ProxyPoolSize,
+		ProxyBalance:       config.ProxyBalance,
+		RpcTimeout:         config.RpcTimeout,
+		BatchSize:          config.BatchSize,
+		ReadPref:           config.ReadPref,
+		AllowPartial:       config.AllowPartial,
+		Turbo:              config.Turbo,
+		ConsistencyProfile: config.ConsistencyProfile,
+		ServiceProfiles:    config.ServiceProfiles,
+		Backend:            config.Backend,
+		MongoURL:           config.MongoURL,
+		AmpQueueSize:       config.AmpQueueSize,
+		AmpWorkers:         config.AmpWorkers,
+		ReportDir:          config.ReportDir,
+		ReportFormat:       config.ReportFormat,
+	}
+	manager, err := proxy.NewManager(proxyConfig, cancel)
+	if err != nil {
+		log.Fatal(err)
 	}
-
 	defer func() {
-		err := storageClient.Close()
-		if err != nil {
-			log.Error("error")
+		if err := manager.Close(); err != nil {
+			log.Error(err)
 		}
 	}()
 
+	idempotencyStore, err := idempotency.NewSharedStore(manager)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	amplifyOptions := &cfg.AmplifyOptions{
 		Connections:  config.Connections,
 		Concurrency:  config.Concurrency,
 		TotalRequest: config.TotalRequest,
 		QPS:          config.QPS,
 		Timeout:      config.Timeout,
+		Duration:     config.Duration,
 		CPUs:         config.CPUs,
 	}
 
+	skuStorage, err := sku.NewSharedClient(manager)
+	if err != nil {
+		log.Fatal(err)
+	}
+	paymentStorage, err := payment.NewSharedClient(manager)
+	if err != nil {
+		log.Fatal(err)
+	}
+	orderStorage, err := order.NewSharedClient(manager)
+	if err != nil {
+		log.Fatal(err)
+	}
+	userStorage, err := user.NewSharedClient(manager)
+	if err != nil {
+		log.Fatal(err)
+	}
+	productStorage, err := product.NewSharedClient(manager)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	skuService := &sku.Service{
-		Storage:   storageClient,
+		Storage:   skuStorage,
 		Amplifier: amplifyOptions,
 	}
 	paymentService := &payment.Service{
-		Storage:   *payment.NewClient(proxyConfig, cancel),
-		Amplifier: amplifyOptions,
+		Storage:     paymentStorage,
+		Amplifier:   amplifyOptions,
+		Providers:   providers,
+		Idempotency: idempotencyStore,
 	}
 
 	orderService := &order.Service{
-		Storage:   storageClient,
-		Amplifier: amplifyOptions,
+		Storage:     orderStorage,
+		Payment:     *paymentService,
+		Amplifier:   amplifyOptions,
+		SkuService:  skuService,
+		Idempotency: idempotencyStore,
 	}
 
 	userService := &user.Service{
-		Storage:   *user.NewClient(proxyConfig, cancel),
+		Storage:   userStorage,
 		Amplifier: amplifyOptions,
 	}
 
 	productService := &product.Service{
-		Storage:    *product.NewClient(proxyConfig, cancel),
+		Storage:    productStorage,
 		Amplifier:  amplifyOptions,
 		SkuService: skuService,
 	}
@@ -123,10 +226,59 @@ func main() {
 	productpb.RegisterProductServiceServer(svr, productService)
 	userpb.RegisterUserServiceServer(svr, userService)
 
+	monitor := health.NewMonitor(&config.HealthConfig)
+	monitor.Add("skupb.SkuService", health.CheckerOf(skuService.Storage))
+	monitor.Add("paymentpb.PaymentService", health.CheckerOf(paymentService.Storage))
+	monitor.Add("orderpb.OrderService", health.CheckerOf(orderService.Storage),
+		health.CheckerOf(paymentService.Storage), health.CheckerOf(skuService.Storage))
+	monitor.Add("productpb.ProductService", health.CheckerOf(productService.Storage), health.CheckerOf(skuService.Storage))
+	monitor.Add("userpb.UserService", health.CheckerOf(userService.Storage))
+	monitor.Register(svr)
+	monitor.Start()
+
 	reflection.Register(svr)
 
+	endpoint := fmt.Sprintf("%s:%d", "127.0.0.1", config.ServerPort)
+	var gateway *http.Server
+	if config.GatewayPort > 0 {
+		gatewayTLS, err := config.GatewayTLS()
+		if err != nil {
+			log.Fatal(err)
+		}
+		gateway, err = newGateway(ctx, endpoint, fmt.Sprintf("%s:%d", "127.0.0.1", config.GatewayPort), serverTLS, gatewayTLS)
+		if err != nil {
+			log.Fatal(err)
+		}
+		go func() {
+			log.Infof("Start http gateway on %s", gateway.Addr)
+			var err error
+			if serverTLS != nil {
+				err = gateway.ListenAndServeTLS("", "")
+			} else {
+				err = gateway.ListenAndServe()
+			}
+			if err != nil && err != http.ErrServerClosed {
+				log.Fatal(err)
+			}
+		}()
+	}
+
+	var metricsServer *http.Server
+	if config.MetricsPort > 0 {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		mux.Handle("/ready", monitor.Handler())
+		metricsServer = &http.Server{Addr: fmt.Sprintf("%s:%d", "127.0.0.1", config.MetricsPort), Handler: mux}
+		go func() {
+			log.Infof("Start serving metrics on %s/metrics", metricsServer.Addr)
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatal(err)
+			}
+		}()
+	}
+
 	go func() {
-		addr := fmt.Sprintf("%s:%d", "127.0.0.1", config.ServerPort)
+		addr := endpoint
 		log.Infof("Start listening on %s", addr)
 		lis, err := net.Listen("tcp4", addr)
 		if err != nil {
@@ -143,5 +295,47 @@ func main() {
 	}
 
 	log.Warn("Got shutdown signal")
+	monitor.Close()
+	for _, httpServer := range []*http.Server{gateway, metricsServer} {
+		if httpServer == nil {
+			continue
+		}
+		if err := httpServer.Shutdown(context.Background()); err != nil {
+			log.Error(err)
+		}
+	}
 	svr.GracefulStop()
 }
+
+// newGateway creates http server translating http/json requests into grpc
+// requests to endpoint, so http load tools hit the same services. x-
+// headers are passed as grpc metadata, eg: x-idempotency-key. with tls,
+// gateway serves https with the same tls config as api server, and dials
+// endpoint on loopback with dialTLS, see cfg.Config.GatewayTLS
+func newGateway(ctx context.Context, endpoint string, addr string, serverTLS *tls.Config, dialTLS *tls.Config) (*http.Server, error) {
+	mux := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(gatewayHeaderMatcher))
+	opts := []grpc.DialOption{grpc.WithInsecure()}
+	if dialTLS != nil {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(dialTLS))}
+	}
+	for _, register := range []func(context.Context, *runtime.ServeMux, string, []grpc.DialOption) error{
+		skupb.RegisterSkuServiceHandlerFromEndpoint,
+		paymentpb.RegisterPaymentServiceHandlerFromEndpoint,
+		orderpb.RegisterOrderServiceHandlerFromEndpoint,
+		productpb.RegisterProductServiceHandlerFromEndpoint,
+		userpb.RegisterUserServiceHandlerFromEndpoint,
+	} {
+		if err := register(ctx, mux, endpoint, opts); err != nil {
+			return nil, err
+		}
+	}
+	return &http.Server{Addr: addr, Handler: mux, TLSConfig: serverTLS}, nil
+}
+
+// gatewayHeaderMatcher passes x- headers and traceparent besides default ones
+func gatewayHeaderMatcher(key string) (string, bool) {
+	if key = strings.ToLower(key); strings.HasPrefix(key, "x-") || key == tracing.TraceparentKey {
+		return key, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/xidongc/mongo_ebenchmark/model/sku/skupb"
 	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
 	server "github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"github.com/xidongc/mongo_ebenchmark/pkg/proxy"
 	"os"
 )
 
@@ -41,24 +42,36 @@ func main() {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	proxyConfig := &cfg.ProxyConfig{
-		ProxyAddr:    config.ProxyAddr,
-		ProxyPort:    config.ProxyPort,
-		Secure:       config.Secure,
-		RpcTimeout:   config.RpcTimeout,
-		BatchSize:    config.BatchSize,
-		ReadPref:     config.ReadPref,
-		AllowPartial: config.AllowPartial,
+		ProxyAddr:          config.ProxyAddr,
+		ProxyPort:          config.ProxyPort,
+		Secure:             config.Secure,
+		ProxyCA:            config.ProxyCA,
+		ProxyCert:          config.ProxyCert,
+		ProxyKey:           config.ProxyKey,
+		ProxyServerName:    config.ProxyServerName,
+		ProxyPoolSize:      config.ProxyPoolSize,
+		ProxyBalance:       config.ProxyBalance,
+		RpcTimeout:         config.RpcTimeout,
+		BatchSize:          config.BatchSize,
+		ReadPref:           config.ReadPref,
+		AllowPartial:       config.AllowPartial,
+		Turbo:              config.Turbo,
+		ConsistencyProfile: config.ConsistencyProfile,
+		ServiceProfiles:    config.ServiceProfiles,
+		Backend:            config.Backend,
+		MongoURL:           config.MongoURL,
+		AmpQueueSize:       config.AmpQueueSize,
+		AmpWorkers:         config.AmpWorkers,
+		ReportDir:          config.ReportDir,
+		ReportFormat:       config.ReportFormat,
 	}
-	storageClient := *sku.NewClient(proxyConfig, cancel)
-
-	if config.Turbo {
-		storageClient.Turbo = true
+	manager, err := proxy.NewManager(proxyConfig, cancel)
+	if err != nil {
+		log.Fatal(err)
 	}
-
 	defer func() {
-		err := storageClient.Close()
-		if err != nil {
-			log.Error("error")
+		if err := manager.Close(); err != nil {
+			log.Error(err)
 		}
 	}()
 
@@ -68,16 +81,26 @@ func main() {
 		TotalRequest: config.TotalRequest,
 		QPS:          config.QPS,
 		Timeout:      config.Timeout,
+		Duration:     config.Duration,
 		CPUs:         config.CPUs,
 	}
 
+	skuStorage, err := sku.NewSharedClient(manager)
+	if err != nil {
+		log.Fatal(err)
+	}
+	productStorage, err := product.NewSharedClient(manager)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	skuService := &sku.Service{
-		Storage:   storageClient,
+		Storage:   skuStorage,
 		Amplifier: amplifyOptions,
 	}
 
 	productService := &product.Service{
-		Storage:    *product.NewClient(proxyConfig, cancel),
+		Storage:    productStorage,
 		Amplifier:  amplifyOptions,
 		SkuService: skuService,
 	}
@@ -115,7 +138,7 @@ func main() {
 			Image:             "",
 			SkuLabel:          "dji care",
 			PackageDimensions: nil,
-			Inventory:         &skupb.Inventory{SkuId: int64(uuid.New().ID()), WarehouseId: int64(uuid.New().ID())},
+			Inventory:         &skupb.Inventory{SkuId: int64(uuid.New().ID()), WarehouseId: int64(uuid.New().ID()), Quantity: 1000, Type: skupb.Inventory_Finite},
 			Attributes:        nil,
 			HasBattery:        false,
 			HasLiquid:         false,
@@ -141,6 +164,8 @@ func main() {
 			Inventory: &skupb.Inventory{
 				SkuId:       int64(uuid.New().ID()),
 				WarehouseId: int64(uuid.New().ID()),
+				Quantity:    1000,
+				Type:        skupb.Inventory_Finite,
 			},
 			Attributes:   map[string]string{"type": "drone"},
 			HasBattery:   true,
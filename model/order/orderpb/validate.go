@@ -0,0 +1,76 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package orderpb
+
+import (
+	"errors"
+	"fmt"
+)
+
+// required fields of order requests, checked by validate middleware of
+// api server before order service is called
+var errNoId = errors.New("order id is required")
+
+func (m *NewRequest) Validate() error {
+	if len(m.GetItems()) == 0 {
+		return errors.New("order has no items")
+	}
+	for _, item := range m.GetItems() {
+		if item.GetQuantity() <= 0 {
+			return fmt.Errorf("quantity of item %s should be positive", item.GetName())
+		}
+	}
+	return nil
+}
+
+func (m *GetRequest) Validate() error {
+	if m.GetId() == "" {
+		return errNoId
+	}
+	return nil
+}
+
+func (m *PayRequest) Validate() error {
+	if m.GetId() == "" {
+		return errNoId
+	}
+	if m.GetCard() == nil {
+		return errors.New("card is required")
+	}
+	return nil
+}
+
+func (m *ReturnRequest) Validate() error {
+	if m.GetId() == "" {
+		return errNoId
+	}
+	return nil
+}
+
+func (m *CancelRequest) Validate() error {
+	if m.GetId() == "" {
+		return errNoId
+	}
+	return nil
+}
+
+func (m *FulfilRequest) Validate() error {
+	if m.GetId() == "" {
+		return errNoId
+	}
+	return nil
+}
@@ -19,31 +19,70 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/uuid"
 	"github.com/mitchellh/mapstructure"
 	log "github.com/sirupsen/logrus"
 	"github.com/xidongc-wish/mgo/bson"
 	"github.com/xidongc/mongo_ebenchmark/model/order/orderpb"
 	"github.com/xidongc/mongo_ebenchmark/model/payment/paymentpb"
 	payment "github.com/xidongc/mongo_ebenchmark/model/payment/service"
+	skuService "github.com/xidongc/mongo_ebenchmark/model/sku/service"
 	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"github.com/xidongc/mongo_ebenchmark/pkg/idempotency"
 	"github.com/xidongc/mongo_ebenchmark/pkg/proxy"
+	"github.com/xidongc/mongo_ebenchmark/pkg/tracing"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
 )
 
 const ns = "order"
 
+// Order Service, stock of items is reserved from SkuService if it is set,
+// retried orders with the same idempotency key are replayed from Idempotency
 type Service struct {
-	Storage   proxy.Client
-	Payment   payment.Service
-	Amplifier cfg.Amplifier
+	Storage     proxy.Storage
+	Payment     payment.Service
+	Amplifier   cfg.Amplifier
+	SkuService  *skuService.Service
+	Idempotency *idempotency.Store
 }
 
-// Create Order
+// Create Order in Created status, a retry with the same idempotency key
+// returns the original order without creating another one
 func (s Service) New(ctx context.Context, req *orderpb.NewRequest) (*orderpb.Order, error) {
-	order := orderpb.Order{
-		Currency: req.Currency,
+	response, err := s.Idempotency.Do(ctx, "order.New", req, &orderpb.Order{}, func() (proto.Message, error) {
+		return s.newOrder(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.(*orderpb.Order), nil
+}
+
+// newOrder creates order, amount is computed from items and stock of
+// items is reserved
+func (s Service) newOrder(ctx context.Context, req *orderpb.NewRequest) (*orderpb.Order, error) {
+	amount, err := itemsAmount(req.GetItems())
+	if err != nil {
+		return nil, err
+	}
+	if err = s.reserve(ctx, req.GetItems()); err != nil {
+		return nil, err
+	}
+	now := time.Now().UnixNano()
+	order := &orderpb.Order{
+		Id:       uuid.New().String(),
 		Items:    req.Items,
+		Amount:   amount,
+		Currency: req.Currency,
+		Status:   orderpb.OrderStatus_Created,
 		Metadata: req.Metadata,
 		Shipping: req.Shipping,
+		Created:  now,
+		Updated:  now,
 	}
 	var orders []interface{}
 	orders = append(orders, order)
@@ -51,55 +90,278 @@ func (s Service) New(ctx context.Context, req *orderpb.NewRequest) (*orderpb.Ord
 		Docs: orders,
 		Amp:  s.Amplifier,
 	}
-	err := s.Storage.Insert(ctx, insertQuery)
+	err = s.Storage.Insert(ctx, insertQuery)
 	if err != nil {
 		log.Error(err)
+		s.release(ctx, order.Items)
+		return nil, proxy.ToStatus(err)
 	}
-	return &order, nil
+	return order, nil
 }
 
 // Get Order by id
 func (s Service) Get(ctx context.Context, req *orderpb.GetRequest) (order *orderpb.Order, err error) {
 
 	param := &proxy.QueryParam{
-		Filter:  bson.M{"_id": req.Id},
+		Filter:  bson.M{"id": req.GetId()},
 		FindOne: true,
 		Amp:     s.Amplifier,
 	}
 
 	results, err := s.Storage.Find(ctx, param)
 
-	if err != nil || len(results) > 1 {
+	if err != nil {
 		log.Error(err)
-		return
+		return nil, proxy.ToStatus(err)
+	} else if len(results) > 1 {
+		return nil, status.Errorf(codes.FailedPrecondition, "duplicate order %s", req.GetId())
 	} else if len(results) == 0 {
-		return order, errors.New("no result found")
+		return nil, proxy.ToStatus(proxy.ErrNotFound)
 	}
+	return decode(results[0])
+}
 
-	err = mapstructure.Decode(results[0], order)
+// Pay charges order amount and moves order from Created to Paid, the
+// charge is linked to order by ChargeId, or refunded if a concurrent
+// payment of order wins
+func (s Service) Pay(ctx context.Context, req *orderpb.PayRequest) (order *orderpb.Order, err error) {
+	order, err = s.Get(ctx, &orderpb.GetRequest{Id: req.GetId()})
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+	if order.GetStatus() != orderpb.OrderStatus_Created {
+		return nil, transitionError(order, orderpb.OrderStatus_Paid)
 	}
-	return order, nil
-}
 
-// Pay
-func (s Service) Pay(ctx context.Context, req *orderpb.PayRequest) (order *orderpb.Order, err error) {
 	chargeRequest := &paymentpb.ChargeRequest{
-		PaymentProviderId: req.GetPaymentProviderId(),
+		Currency:          order.GetCurrency(),
+		Amount:            order.GetAmount(),
 		Card:              req.GetCard(),
-		// Amount:  uint64(o.GetAmount()),  TODO need to define order id
+		Statement:         order.GetId(),
+		PaymentProviderId: req.GetPaymentProviderId(),
 	}
-	charge, err := s.Payment.NewCharge(ctx, chargeRequest)
+	// idempotency key of pay request is not reused by charge, a replayed
+	// charge could be refunded as duplicate of its own order
+	paymentCtx, span := tracing.Start(ctx, "payment.Service.NewCharge", tracing.Internal)
+	charge, err := s.Payment.NewCharge(idempotency.WithKey(paymentCtx, ""), chargeRequest)
+	tracing.End(span, err)
 	if err != nil {
-		// TODO do refund logic
-		log.Fatal(err)
+		log.Error(err)
+		return nil, err
 	}
-	log.Info(charge)
-	return
+
+	paid, err := s.transition(ctx, order.GetId(), orderpb.OrderStatus_Created, orderpb.OrderStatus_Paid, bson.M{"chargeid": charge.GetId()})
+	if err != nil {
+		// a concurrent payment won, charge is not linked to order
+		log.Warningf("order %s: refund charge %s not linked: %s", order.GetId(), charge.GetId(), err)
+		refundRequest := &paymentpb.RefundRequest{
+			Id:       charge.GetId(),
+			Reason:   paymentpb.RefundReason_Duplicate,
+			RefundId: charge.GetId(),
+		}
+		if _, refundErr := s.refund(ctx, refundRequest); refundErr != nil {
+			log.Errorf("order %s: refund charge %s error: %s", order.GetId(), charge.GetId(), refundErr)
+		}
+		return nil, err
+	}
+	return paid, nil
+}
+
+// Cancel moves order from Created to Canceled and releases stock
+func (s Service) Cancel(ctx context.Context, req *orderpb.CancelRequest) (order *orderpb.Order, err error) {
+	order, err = s.transition(ctx, req.GetId(), orderpb.OrderStatus_Created, orderpb.OrderStatus_Canceled, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.release(ctx, order.GetItems())
+	return order, nil
+}
+
+// Fulfil moves order from Paid to Fulfilled, shipping carrier and tracking
+// number are recorded
+func (s Service) Fulfil(ctx context.Context, req *orderpb.FulfilRequest) (order *orderpb.Order, err error) {
+	if req.GetTrackingNumber() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "order %s: tracking number is required", req.GetId())
+	}
+	order, err = s.Get(ctx, &orderpb.GetRequest{Id: req.GetId()})
+	if err != nil {
+		return nil, err
+	}
+
+	var shipping bson.M
+	if order.GetShipping() == nil {
+		shipping = bson.M{"shipping": bson.M{"carrier": req.GetCarrier(), "trackingnumber": req.GetTrackingNumber()}}
+	} else {
+		shipping = bson.M{"shipping.carrier": req.GetCarrier(), "shipping.trackingnumber": req.GetTrackingNumber()}
+	}
+	return s.transition(ctx, req.GetId(), orderpb.OrderStatus_Paid, orderpb.OrderStatus_Fulfilled, shipping)
 }
 
+// Return moves order from Fulfilled to Returned, charge of order is
+// refunded with order id as refund id, so a retried return is refunded
+// once, and stock is released
 func (s Service) Return(ctx context.Context, req *orderpb.ReturnRequest) (order *orderpb.Order, err error) {
-	// TODO
+	order, err = s.Get(ctx, &orderpb.GetRequest{Id: req.GetId()})
+	if err != nil {
+		return nil, err
+	}
+	if order.GetStatus() != orderpb.OrderStatus_Fulfilled {
+		return nil, transitionError(order, orderpb.OrderStatus_Returned)
+	}
+	if order.GetChargeId() != "" {
+		refundRequest := &paymentpb.RefundRequest{
+			Id:       order.GetChargeId(),
+			Reason:   paymentpb.RefundReason_RequestedByCustomer,
+			RefundId: order.GetId(),
+		}
+		if _, err = s.refund(ctx, refundRequest); err != nil {
+			log.Error(err)
+			return nil, err
+		}
+	}
+
+	order, err = s.transition(ctx, req.GetId(), orderpb.OrderStatus_Fulfilled, orderpb.OrderStatus_Returned, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.release(ctx, order.GetItems())
+	return order, nil
+}
+
+// transition conditionally moves order from status to status with
+// FindAndModify, so only one of concurrent transitions succeeds, fields
+// are set together with status
+func (s Service) transition(ctx context.Context, id string, from orderpb.OrderStatus, to orderpb.OrderStatus, fields bson.M) (order *orderpb.Order, err error) {
+	set := bson.M{
+		"status":  to,
+		"updated": time.Now().UnixNano(),
+	}
+	for key, value := range fields {
+		set[key] = value
+	}
+
+	param := &proxy.FindModifyParam{
+		Filter:  bson.M{"id": id, "status": from},
+		Desired: bson.M{"$set": set},
+		Mode:    proxy.FindAndUpdate,
+		Amp:     s.Amplifier,
+	}
+
+	result, err := s.Storage.FindAndModify(ctx, param)
+	if err != nil && !errors.Is(err, proxy.ErrNotFound) {
+		log.Error(err)
+		return nil, proxy.ToStatus(err)
+	}
+	if err == nil && result != nil {
+		return decode(result)
+	}
+
+	// no order in from status, report current status of order
+	order, err = s.Get(ctx, &orderpb.GetRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return nil, transitionError(order, to)
+}
+
+// reserve takes stock of product items by sku name, warehouse reserved
+// from is recorded in item, items reserved so far are released if
+// one of them fails
+func (s Service) reserve(ctx context.Context, items []*orderpb.Item) error {
+	if s.SkuService == nil {
+		return nil
+	}
+	for i, item := range items {
+		if item.GetType() != orderpb.ItemType_product {
+			continue
+		}
+		skuCtx, span := tracing.Start(ctx, "sku.Service.Reserve", tracing.Internal)
+		warehouseId, err := s.SkuService.Reserve(skuCtx, item.GetName(), item.GetQuantity())
+		tracing.End(span, err)
+		if err != nil {
+			s.release(ctx, items[:i])
+			return err
+		}
+		item.WarehouseId = warehouseId
+	}
+	return nil
+}
+
+// release puts stock of product items back, error is logged only since
+// order status is moved already
+func (s Service) release(ctx context.Context, items []*orderpb.Item) {
+	if s.SkuService == nil {
+		return
+	}
+	for _, item := range items {
+		if item.GetType() != orderpb.ItemType_product {
+			continue
+		}
+		skuCtx, span := tracing.Start(ctx, "sku.Service.Release", tracing.Internal)
+		err := s.SkuService.Release(skuCtx, item.GetName(), item.GetWarehouseId(), item.GetQuantity())
+		tracing.End(span, err)
+		if err != nil {
+			log.Errorf("%s: release %d of sku %s error: %s", ns, item.GetQuantity(), item.GetName(), err)
+		}
+	}
+}
+
+// refund refunds charge of order through payment service
+func (s Service) refund(ctx context.Context, req *paymentpb.RefundRequest) (*paymentpb.Charge, error) {
+	ctx, span := tracing.Start(ctx, "payment.Service.RefundCharge", tracing.Internal)
+	charge, err := s.Payment.RefundCharge(ctx, req)
+	tracing.End(span, err)
+	return charge, err
+}
+
+// Create Order Service client
+func NewClient(config *cfg.ProxyConfig, cancel context.CancelFunc) (client proxy.Storage, err error) {
+	if client, err = proxy.NewStorage(config, ns, cancel); err != nil {
+		return nil, fmt.Errorf("%s: create storage error with: %w", ns, err)
+	}
 	return
 }
+
+// Create Order Service client on connections shared by manager
+func NewSharedClient(manager *proxy.Manager) (client proxy.Storage, err error) {
+	if client, err = manager.Storage(ns); err != nil {
+		return nil, fmt.Errorf("%s: create storage error with: %w", ns, err)
+	}
+	return
+}
+
+// itemsAmount sums amount of items, discount items are subtracted
+func itemsAmount(items []*orderpb.Item) (amount uint64, err error) {
+	if len(items) == 0 {
+		return 0, status.Error(codes.InvalidArgument, "order has no items")
+	}
+	var total int64
+	for _, item := range items {
+		if item.GetQuantity() <= 0 || item.GetAmount() < 0 {
+			return 0, status.Errorf(codes.InvalidArgument, "invalid item %s", item.GetName())
+		}
+		if item.GetType() == orderpb.ItemType_discount {
+			total -= item.GetAmount() * item.GetQuantity()
+		} else {
+			total += item.GetAmount() * item.GetQuantity()
+		}
+	}
+	if total < 0 {
+		return 0, status.Error(codes.InvalidArgument, "order amount is negative")
+	}
+	return uint64(total), nil
+}
+
+// transitionError rejects moving order into status it can not reach
+func transitionError(order *orderpb.Order, to orderpb.OrderStatus) error {
+	return status.Errorf(codes.FailedPrecondition, "order %s is %s, can not move to %s", order.GetId(), order.GetStatus(), to)
+}
+
+// decode converts stored document into order
+func decode(doc bson.M) (order *orderpb.Order, err error) {
+	if err = mapstructure.Decode(doc, &order); err != nil {
+		log.Error(err)
+		return nil, proxy.ToStatus(err)
+	}
+	return order, nil
+}
@@ -15,3 +15,301 @@
  */
 
 package service
+
+import (
+	"github.com/xidongc-wish/mgo/bson"
+	"github.com/xidongc/mongo_ebenchmark/model/order/orderpb"
+	"github.com/xidongc/mongo_ebenchmark/model/payment/paymentpb"
+	payment "github.com/xidongc/mongo_ebenchmark/model/payment/service"
+	skuService "github.com/xidongc/mongo_ebenchmark/model/sku/service"
+	"github.com/xidongc/mongo_ebenchmark/model/sku/skupb"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"github.com/xidongc/mongo_ebenchmark/pkg/idempotency"
+	"github.com/xidongc/mongo_ebenchmark/pkg/proxy"
+	"github.com/xidongc/mongo_ebenchmark/pkg/proxy/proxytest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"sync"
+	"testing"
+)
+
+func TestOrderLifecycle(t *testing.T) {
+	server, ctx, cancel := proxytest.Start(t)
+
+	storage, err := NewClient(server.Config(), cancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paymentStorage, err := payment.NewClient(server.Config(), cancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := &Service{
+		Storage: storage,
+		Payment: payment.Service{
+			Storage:   paymentStorage,
+			Amplifier: cfg.MicroAmplifier(),
+		},
+		Amplifier: cfg.MicroAmplifier(),
+	}
+	defer service.Storage.Close()
+	defer service.Payment.Storage.Close()
+
+	newOrder := func() *orderpb.Order {
+		order, err := service.New(ctx, &orderpb.NewRequest{
+			Currency: paymentpb.Currency_USD,
+			Items: []*orderpb.Item{
+				{ProductId: "mavic", Name: "mavic air", Quantity: 2, Amount: 1000},
+				{ProductId: "care", Name: "care refresh", Quantity: 1, Amount: 300},
+				{Name: "coupon", Quantity: 1, Amount: 100, Type: orderpb.ItemType_discount},
+			},
+			Shipping: &orderpb.Shipping{Name: "xidong"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return order
+	}
+	expectCode := func(err error, code codes.Code) {
+		t.Helper()
+		if status.Code(err) != code {
+			t.Errorf("expect %s, got %v", code, err)
+		}
+	}
+	pay := &orderpb.PayRequest{
+		Card:              &paymentpb.Card{Type: paymentpb.CardType_Visa},
+		PaymentProviderId: paymentpb.PaymentProviderId_AliPay,
+	}
+
+	order := newOrder()
+	if order.GetId() == "" || order.GetStatus() != orderpb.OrderStatus_Created {
+		t.Fatalf("unexpected new order %+v", order)
+	}
+	if order.GetAmount() != 2200 {
+		t.Errorf("expect amount 2200, got %d", order.GetAmount())
+	}
+
+	_, err = service.Fulfil(ctx, &orderpb.FulfilRequest{Id: order.GetId(), Carrier: "ups", TrackingNumber: "1z"})
+	expectCode(err, codes.FailedPrecondition)
+
+	pay.Id = order.GetId()
+	paid, err := service.Pay(ctx, pay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paid.GetStatus() != orderpb.OrderStatus_Paid || paid.GetChargeId() == "" {
+		t.Errorf("unexpected paid order %+v", paid)
+	}
+	_, err = service.Pay(ctx, pay)
+	expectCode(err, codes.FailedPrecondition)
+	_, err = service.Cancel(ctx, &orderpb.CancelRequest{Id: order.GetId()})
+	expectCode(err, codes.FailedPrecondition)
+
+	_, err = service.Fulfil(ctx, &orderpb.FulfilRequest{Id: order.GetId(), Carrier: "ups"})
+	expectCode(err, codes.InvalidArgument)
+	fulfilled, err := service.Fulfil(ctx, &orderpb.FulfilRequest{Id: order.GetId(), Carrier: "ups", TrackingNumber: "1z"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fulfilled.GetStatus() != orderpb.OrderStatus_Fulfilled || fulfilled.GetShipping().GetTrackingNumber() != "1z" {
+		t.Errorf("unexpected fulfilled order %+v", fulfilled)
+	}
+	if fulfilled.GetShipping().GetName() != "xidong" || fulfilled.GetChargeId() != paid.GetChargeId() {
+		t.Errorf("fulfil should keep shipping name and charge, got %+v", fulfilled)
+	}
+
+	returned, err := service.Return(ctx, &orderpb.ReturnRequest{Id: order.GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if returned.GetStatus() != orderpb.OrderStatus_Returned {
+		t.Errorf("expect returned, got %s", returned.GetStatus())
+	}
+	charge, err := service.Payment.Get(ctx, &paymentpb.GetRequest{Id: paid.GetChargeId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !charge.GetRefunded() || charge.GetRefundAmount() != 2200 {
+		t.Errorf("expect charge of returned order refunded, got %+v", charge)
+	}
+	_, err = service.Return(ctx, &orderpb.ReturnRequest{Id: order.GetId()})
+	expectCode(err, codes.FailedPrecondition)
+
+	canceled, err := service.Cancel(ctx, &orderpb.CancelRequest{Id: newOrder().GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if canceled.GetStatus() != orderpb.OrderStatus_Canceled {
+		t.Errorf("expect canceled, got %s", canceled.GetStatus())
+	}
+	pay.Id = canceled.GetId()
+	_, err = service.Pay(ctx, pay)
+	expectCode(err, codes.FailedPrecondition)
+
+	_, err = service.Cancel(ctx, &orderpb.CancelRequest{Id: "not exist"})
+	expectCode(err, codes.NotFound)
+	_, err = service.New(ctx, &orderpb.NewRequest{})
+	expectCode(err, codes.InvalidArgument)
+}
+
+func TestOrderPayOnce(t *testing.T) {
+	server, ctx, cancel := proxytest.Start(t)
+
+	storage, err := NewClient(server.Config(), cancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paymentStorage, err := payment.NewClient(server.Config(), cancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := &Service{
+		Storage: storage,
+		Payment: payment.Service{
+			Storage: paymentStorage,
+		},
+	}
+	defer service.Storage.Close()
+	defer service.Payment.Storage.Close()
+
+	order, err := service.New(ctx, &orderpb.NewRequest{
+		Items: []*orderpb.Item{{Name: "mavic air", Quantity: 1, Amount: 1000}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	paid := 0
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pay := &orderpb.PayRequest{
+				Id:                order.GetId(),
+				Card:              &paymentpb.Card{Type: paymentpb.CardType_Visa},
+				PaymentProviderId: paymentpb.PaymentProviderId_Mock,
+			}
+			_, err := service.Pay(ctx, pay)
+			if err == nil {
+				lock.Lock()
+				paid++
+				lock.Unlock()
+			} else if status.Code(err) != codes.FailedPrecondition {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if paid != 1 {
+		t.Errorf("expect order paid once, got %d", paid)
+	}
+}
+
+func TestOrderReserve(t *testing.T) {
+	server, ctx, cancel := proxytest.Start(t)
+
+	skuStorage, err := skuService.NewClient(server.Config(), cancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skus := &skuService.Service{Storage: skuStorage}
+	defer skus.Storage.Close()
+	storage, err := NewClient(server.Config(), cancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := &Service{
+		Storage:    storage,
+		SkuService: skus,
+	}
+	defer service.Storage.Close()
+
+	inventory := &skupb.Inventory{SkuId: 1, WarehouseId: 7, Quantity: 3, Type: skupb.Inventory_Finite}
+	if _, err := skus.New(ctx, &skupb.UpsertRequest{Name: "mavic air", Inventory: inventory}); err != nil {
+		t.Fatal(err)
+	}
+	stock := func() int64 {
+		sku, err := skus.Get(ctx, &skupb.GetRequest{Name: "mavic air"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sku.GetInventory()[0].GetQuantity()
+	}
+	newOrder := func() (*orderpb.Order, error) {
+		return service.New(ctx, &orderpb.NewRequest{
+			Items: []*orderpb.Item{
+				{Name: "mavic air", Quantity: 2, Amount: 1000},
+				{Name: "coupon", Quantity: 1, Amount: 100, Type: orderpb.ItemType_discount},
+			},
+		})
+	}
+
+	order, err := newOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.GetItems()[0].GetWarehouseId() != 7 || stock() != 1 {
+		t.Errorf("expect 2 reserved from warehouse 7, got %+v, stock %d", order.GetItems()[0], stock())
+	}
+	if _, err := newOrder(); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expect out of stock, got %v", err)
+	}
+
+	if _, err := service.Cancel(ctx, &orderpb.CancelRequest{Id: order.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+	if stock() != 3 {
+		t.Errorf("expect stock released, got %d", stock())
+	}
+	if _, err := service.Cancel(ctx, &orderpb.CancelRequest{Id: order.GetId()}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expect canceled once, got %v", err)
+	}
+	if stock() != 3 {
+		t.Errorf("expect stock released once, got %d", stock())
+	}
+}
+
+func TestOrderIdempotency(t *testing.T) {
+	server, ctx, cancel := proxytest.Start(t)
+
+	store, err := idempotency.NewStore(server.Config(), cancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Storage.Close()
+	storage, err := NewClient(server.Config(), cancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := &Service{
+		Storage:     storage,
+		Idempotency: store,
+	}
+	defer service.Storage.Close()
+
+	req := &orderpb.NewRequest{
+		Items: []*orderpb.Item{{Name: "mavic air", Quantity: 1, Amount: 1000}},
+	}
+	retried := metadata.NewIncomingContext(ctx, metadata.Pairs(idempotency.MetadataKey, "order-1"))
+	first, err := service.New(retried, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := service.New(retried, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.GetId() != first.GetId() || second.GetAmount() != first.GetAmount() {
+		t.Errorf("expect original order replayed, got %+v", second)
+	}
+	if _, err = service.New(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	count, err := service.Storage.Count(ctx, &proxy.QueryParam{Filter: bson.M{}})
+	if err != nil || count != 2 {
+		t.Errorf("expect 2 orders, got %d, %v", count, err)
+	}
+}
@@ -37,6 +37,7 @@ const (
 	PaymentProviderId_AliPay            PaymentProviderId = 1
 	PaymentProviderId_Paypal            PaymentProviderId = 2
 	PaymentProviderId_WeChat            PaymentProviderId = 3
+	PaymentProviderId_Mock              PaymentProviderId = 4 // offline provider for load tests
 )
 
 // Enum value maps for PaymentProviderId.
@@ -46,12 +47,14 @@ var (
 		1: "AliPay",
 		2: "Paypal",
 		3: "WeChat",
+		4: "Mock",
 	}
 	PaymentProviderId_value = map[string]int32{
 		"PROVIDER_Reserved": 0,
 		"AliPay":            1,
 		"Paypal":            2,
 		"WeChat":            3,
+		"Mock":              4,
 	}
 )
 
@@ -768,9 +771,10 @@ type RefundRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id     string       `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Amount uint64       `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
-	Reason RefundReason `protobuf:"varint,3,opt,name=reason,proto3,enum=paymentpb.RefundReason" json:"reason,omitempty"`
+	Id       string       `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Amount   uint64       `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"` // remaining amount is refunded if 0
+	Reason   RefundReason `protobuf:"varint,3,opt,name=reason,proto3,enum=paymentpb.RefundReason" json:"reason,omitempty"`
+	RefundId string       `protobuf:"bytes,4,opt,name=refundId,proto3" json:"refundId,omitempty"` // retries with the same refund id are refunded once
 }
 
 func (x *RefundRequest) Reset() {
@@ -826,6 +830,13 @@ func (x *RefundRequest) GetReason() RefundReason {
 	return RefundReason_GeneralError
 }
 
+func (x *RefundRequest) GetRefundId() string {
+	if x != nil {
+		return x.RefundId
+	}
+	return ""
+}
+
 type GetRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -878,17 +889,18 @@ type Charge struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id           string    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	MerchantId   string    `protobuf:"bytes,2,opt,name=merchantId,proto3" json:"merchantId,omitempty"`
-	ChargeAmount uint64    `protobuf:"varint,3,opt,name=chargeAmount,proto3" json:"chargeAmount,omitempty"`
-	RefundAmount uint64    `protobuf:"varint,4,opt,name=refundAmount,proto3" json:"refundAmount,omitempty"`
-	Refunds      []*Refund `protobuf:"bytes,5,rep,name=refunds,proto3" json:"refunds,omitempty"`
-	Currency     Currency  `protobuf:"varint,6,opt,name=currency,proto3,enum=paymentpb.Currency" json:"currency,omitempty"`
-	UserId       string    `protobuf:"bytes,7,opt,name=userId,proto3" json:"userId,omitempty"`
-	Paid         bool      `protobuf:"varint,8,opt,name=paid,proto3" json:"paid,omitempty"`
-	Refunded     bool      `protobuf:"varint,9,opt,name=refunded,proto3" json:"refunded,omitempty"`
-	Created      int64     `protobuf:"varint,998,opt,name=created,proto3" json:"created,omitempty"`
-	Updated      int64     `protobuf:"varint,999,opt,name=updated,proto3" json:"updated,omitempty"`
+	Id                string            `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	MerchantId        string            `protobuf:"bytes,2,opt,name=merchantId,proto3" json:"merchantId,omitempty"`
+	ChargeAmount      uint64            `protobuf:"varint,3,opt,name=chargeAmount,proto3" json:"chargeAmount,omitempty"`
+	RefundAmount      uint64            `protobuf:"varint,4,opt,name=refundAmount,proto3" json:"refundAmount,omitempty"`
+	Refunds           []*Refund         `protobuf:"bytes,5,rep,name=refunds,proto3" json:"refunds,omitempty"`
+	Currency          Currency          `protobuf:"varint,6,opt,name=currency,proto3,enum=paymentpb.Currency" json:"currency,omitempty"`
+	UserId            string            `protobuf:"bytes,7,opt,name=userId,proto3" json:"userId,omitempty"`
+	Paid              bool              `protobuf:"varint,8,opt,name=paid,proto3" json:"paid,omitempty"`
+	Refunded          bool              `protobuf:"varint,9,opt,name=refunded,proto3" json:"refunded,omitempty"`
+	PaymentProviderId PaymentProviderId `protobuf:"varint,10,opt,name=paymentProviderId,proto3,enum=paymentpb.PaymentProviderId" json:"paymentProviderId,omitempty"`
+	Created           int64             `protobuf:"varint,998,opt,name=created,proto3" json:"created,omitempty"`
+	Updated           int64             `protobuf:"varint,999,opt,name=updated,proto3" json:"updated,omitempty"`
 }
 
 func (x *Charge) Reset() {
@@ -986,6 +998,13 @@ func (x *Charge) GetRefunded() bool {
 	return false
 }
 
+func (x *Charge) GetPaymentProviderId() PaymentProviderId {
+	if x != nil {
+		return x.PaymentProviderId
+	}
+	return PaymentProviderId_PROVIDER_Reserved
+}
+
 func (x *Charge) GetCreated() int64 {
 	if x != nil {
 		return x.Created
@@ -1009,6 +1028,7 @@ type Refund struct {
 	ProviderRefundId string       `protobuf:"bytes,2,opt,name=providerRefundId,proto3" json:"providerRefundId,omitempty"`
 	Reason           RefundReason `protobuf:"varint,3,opt,name=reason,proto3,enum=paymentpb.RefundReason" json:"reason,omitempty"`
 	Created          int64        `protobuf:"varint,4,opt,name=created,proto3" json:"created,omitempty"`
+	Id               string       `protobuf:"bytes,5,opt,name=id,proto3" json:"id,omitempty"`
 }
 
 func (x *Refund) Reset() {
@@ -1071,6 +1091,13 @@ func (x *Refund) GetCreated() int64 {
 	return 0
 }
 
+func (x *Refund) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
 type Card struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1197,171 +1224,179 @@ var file_paymentpb_payment_proto_rawDesc = []byte{
 	0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
 	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
 	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
-	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x68, 0x0a, 0x0d, 0x52, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2f,
-	0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x84, 0x01, 0x0a, 0x0d, 0x52, 0x65, 0x66, 0x75, 0x6e, 0x64,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12,
+	0x2f, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x17, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x66, 0x75,
+	0x6e, 0x64, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x49, 0x64, 0x22, 0x1c, 0x0a, 0x0a,
+	0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0xa8, 0x03, 0x0a, 0x06, 0x43,
+	0x68, 0x61, 0x72, 0x67, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x65, 0x72, 0x63, 0x68, 0x61, 0x6e,
+	0x74, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x65, 0x72, 0x63, 0x68,
+	0x61, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x41,
+	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x63, 0x68, 0x61,
+	0x72, 0x67, 0x65, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x22, 0x0a, 0x0c, 0x72, 0x65, 0x66,
+	0x75, 0x6e, 0x64, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0c, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2b, 0x0a,
+	0x07, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11,
 	0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x66, 0x75, 0x6e,
-	0x64, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22,
-	0x1c, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a,
-	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0xdc, 0x02,
-	0x0a, 0x06, 0x43, 0x68, 0x61, 0x72, 0x67, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x65, 0x72, 0x63,
-	0x68, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x65,
-	0x72, 0x63, 0x68, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x68, 0x61, 0x72,
-	0x67, 0x65, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c,
-	0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x22, 0x0a, 0x0c,
-	0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x0c, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
-	0x12, 0x2b, 0x0a, 0x07, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x11, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x2e, 0x52, 0x65,
-	0x66, 0x75, 0x6e, 0x64, 0x52, 0x07, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x73, 0x12, 0x2f, 0x0a,
-	0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32,
-	0x13, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x2e, 0x43, 0x75, 0x72, 0x72,
-	0x65, 0x6e, 0x63, 0x79, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x16,
-	0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
-	0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x69, 0x64, 0x18, 0x08,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x70, 0x61, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65,
-	0x66, 0x75, 0x6e, 0x64, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65,
-	0x66, 0x75, 0x6e, 0x64, 0x65, 0x64, 0x12, 0x19, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
-	0x64, 0x18, 0xe6, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
-	0x64, 0x12, 0x19, 0x0a, 0x07, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x18, 0xe7, 0x07, 0x20,
-	0x01, 0x28, 0x03, 0x52, 0x07, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x22, 0xa3, 0x01, 0x0a,
-	0x06, 0x52, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x72, 0x65, 0x66, 0x75, 0x6e,
-	0x64, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x72,
-	0x65, 0x66, 0x75, 0x6e, 0x64, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2a, 0x0a, 0x10, 0x70,
-	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x52, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x49, 0x64, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x52,
-	0x65, 0x66, 0x75, 0x6e, 0x64, 0x49, 0x64, 0x12, 0x2f, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f,
-	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e,
-	0x74, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e,
-	0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61,
-	0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74,
-	0x65, 0x64, 0x22, 0xd5, 0x01, 0x0a, 0x04, 0x43, 0x61, 0x72, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x4e,
-	0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x4e, 0x75, 0x6d,
-	0x62, 0x65, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x4d, 0x6f, 0x6e,
-	0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65,
-	0x4d, 0x6f, 0x6e, 0x74, 0x68, 0x12, 0x1e, 0x0a, 0x0a, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x59,
-	0x65, 0x61, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x45, 0x78, 0x70, 0x69, 0x72,
-	0x65, 0x59, 0x65, 0x61, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x46, 0x69, 0x72, 0x73, 0x74, 0x4e, 0x61,
-	0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x46, 0x69, 0x72, 0x73, 0x74, 0x4e,
-	0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x4c, 0x61, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x4c, 0x61, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12,
-	0x10, 0x0a, 0x03, 0x43, 0x56, 0x43, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x43, 0x56,
-	0x43, 0x12, 0x27, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32,
-	0x13, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x2e, 0x43, 0x61, 0x72, 0x64,
-	0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x2a, 0x4e, 0x0a, 0x11, 0x50, 0x61,
+	0x64, 0x52, 0x07, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x73, 0x12, 0x2f, 0x0a, 0x08, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x70,
+	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63,
+	0x79, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x75,
+	0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65,
+	0x72, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x69, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x04, 0x70, 0x61, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x66, 0x75, 0x6e,
+	0x64, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x66, 0x75, 0x6e,
+	0x64, 0x65, 0x64, 0x12, 0x4a, 0x0a, 0x11, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x50, 0x72,
+	0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x49, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1c,
+	0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x49, 0x64, 0x52, 0x11, 0x70, 0x61,
 	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x49, 0x64, 0x12,
-	0x15, 0x0a, 0x11, 0x50, 0x52, 0x4f, 0x56, 0x49, 0x44, 0x45, 0x52, 0x5f, 0x52, 0x65, 0x73, 0x65,
-	0x72, 0x76, 0x65, 0x64, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x41, 0x6c, 0x69, 0x50, 0x61, 0x79,
-	0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x50, 0x61, 0x79, 0x70, 0x61, 0x6c, 0x10, 0x02, 0x12, 0x0a,
-	0x0a, 0x06, 0x57, 0x65, 0x43, 0x68, 0x61, 0x74, 0x10, 0x03, 0x2a, 0x26, 0x0a, 0x0c, 0x43, 0x68,
-	0x61, 0x72, 0x67, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x08, 0x0a, 0x04, 0x50, 0x61,
-	0x69, 0x64, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x52, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x65, 0x64,
-	0x10, 0x01, 0x2a, 0x53, 0x0a, 0x0c, 0x52, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x52, 0x65, 0x61, 0x73,
-	0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x0c, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x6c, 0x45, 0x72, 0x72,
-	0x6f, 0x72, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x46, 0x72, 0x61, 0x75, 0x64, 0x10, 0x01, 0x12,
-	0x0d, 0x0a, 0x09, 0x44, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x10, 0x02, 0x12, 0x17,
-	0x0a, 0x13, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x42, 0x79, 0x43, 0x75, 0x73,
-	0x74, 0x6f, 0x6d, 0x65, 0x72, 0x10, 0x03, 0x2a, 0x73, 0x0a, 0x08, 0x43, 0x61, 0x72, 0x64, 0x54,
-	0x79, 0x70, 0x65, 0x12, 0x11, 0x0a, 0x0d, 0x43, 0x41, 0x52, 0x44, 0x5f, 0x52, 0x65, 0x73, 0x65,
-	0x72, 0x76, 0x65, 0x64, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x4d, 0x61, 0x73, 0x74, 0x65, 0x72,
-	0x63, 0x61, 0x72, 0x64, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x56, 0x69, 0x73, 0x61, 0x10, 0x02,
-	0x12, 0x13, 0x0a, 0x0f, 0x41, 0x6d, 0x65, 0x72, 0x69, 0x63, 0x61, 0x6e, 0x45, 0x78, 0x70, 0x72,
-	0x65, 0x73, 0x73, 0x10, 0x03, 0x12, 0x07, 0x0a, 0x03, 0x4a, 0x43, 0x42, 0x10, 0x04, 0x12, 0x0c,
-	0x0a, 0x08, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x10, 0x05, 0x12, 0x0e, 0x0a, 0x0a,
-	0x44, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x43, 0x6c, 0x75, 0x62, 0x10, 0x06, 0x2a, 0x9d, 0x09, 0x0a,
-	0x08, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x10, 0x0a, 0x0c, 0x43, 0x55, 0x52,
-	0x5f, 0x52, 0x45, 0x53, 0x45, 0x52, 0x56, 0x45, 0x44, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x41,
-	0x46, 0x4e, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x4c, 0x4c, 0x10, 0x02, 0x12, 0x07, 0x0a,
-	0x03, 0x41, 0x4d, 0x44, 0x10, 0x03, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x4e, 0x47, 0x10, 0x04, 0x12,
-	0x07, 0x0a, 0x03, 0x41, 0x52, 0x53, 0x10, 0x05, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x55, 0x44, 0x10,
-	0x06, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x57, 0x47, 0x10, 0x07, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x5a,
-	0x4e, 0x10, 0x08, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x41, 0x4d, 0x10, 0x09, 0x12, 0x07, 0x0a, 0x03,
-	0x42, 0x42, 0x44, 0x10, 0x0a, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x47, 0x4e, 0x10, 0x0b, 0x12, 0x07,
-	0x0a, 0x03, 0x42, 0x48, 0x44, 0x10, 0x0c, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x4d, 0x44, 0x10, 0x0d,
-	0x12, 0x07, 0x0a, 0x03, 0x42, 0x4e, 0x44, 0x10, 0x0e, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x4f, 0x42,
-	0x10, 0x0f, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x52, 0x4c, 0x10, 0x10, 0x12, 0x07, 0x0a, 0x03, 0x42,
-	0x53, 0x44, 0x10, 0x11, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x57, 0x50, 0x10, 0x12, 0x12, 0x07, 0x0a,
-	0x03, 0x42, 0x59, 0x4e, 0x10, 0x13, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x59, 0x52, 0x10, 0x14, 0x12,
-	0x07, 0x0a, 0x03, 0x42, 0x5a, 0x44, 0x10, 0x15, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x41, 0x44, 0x10,
-	0x16, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x4c, 0x50, 0x10, 0x17, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x4e,
-	0x59, 0x10, 0x18, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x4f, 0x50, 0x10, 0x19, 0x12, 0x07, 0x0a, 0x03,
-	0x43, 0x52, 0x43, 0x10, 0x1a, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x55, 0x50, 0x10, 0x1b, 0x12, 0x07,
-	0x0a, 0x03, 0x43, 0x5a, 0x4b, 0x10, 0x1c, 0x12, 0x07, 0x0a, 0x03, 0x44, 0x4b, 0x4b, 0x10, 0x1d,
-	0x12, 0x07, 0x0a, 0x03, 0x44, 0x4f, 0x50, 0x10, 0x1e, 0x12, 0x07, 0x0a, 0x03, 0x44, 0x5a, 0x44,
-	0x10, 0x1f, 0x12, 0x07, 0x0a, 0x03, 0x45, 0x45, 0x4b, 0x10, 0x20, 0x12, 0x07, 0x0a, 0x03, 0x45,
-	0x47, 0x50, 0x10, 0x21, 0x12, 0x07, 0x0a, 0x03, 0x45, 0x55, 0x52, 0x10, 0x22, 0x12, 0x07, 0x0a,
-	0x03, 0x46, 0x4a, 0x44, 0x10, 0x23, 0x12, 0x07, 0x0a, 0x03, 0x46, 0x4b, 0x50, 0x10, 0x24, 0x12,
-	0x07, 0x0a, 0x03, 0x47, 0x42, 0x50, 0x10, 0x25, 0x12, 0x07, 0x0a, 0x03, 0x47, 0x47, 0x50, 0x10,
-	0x26, 0x12, 0x07, 0x0a, 0x03, 0x47, 0x48, 0x43, 0x10, 0x27, 0x12, 0x07, 0x0a, 0x03, 0x47, 0x49,
-	0x50, 0x10, 0x28, 0x12, 0x07, 0x0a, 0x03, 0x47, 0x54, 0x51, 0x10, 0x29, 0x12, 0x07, 0x0a, 0x03,
-	0x47, 0x59, 0x44, 0x10, 0x2a, 0x12, 0x07, 0x0a, 0x03, 0x48, 0x4b, 0x44, 0x10, 0x2b, 0x12, 0x07,
-	0x0a, 0x03, 0x48, 0x4e, 0x4c, 0x10, 0x2c, 0x12, 0x07, 0x0a, 0x03, 0x48, 0x52, 0x4b, 0x10, 0x2d,
-	0x12, 0x07, 0x0a, 0x03, 0x48, 0x55, 0x46, 0x10, 0x2e, 0x12, 0x07, 0x0a, 0x03, 0x49, 0x44, 0x52,
-	0x10, 0x2f, 0x12, 0x07, 0x0a, 0x03, 0x49, 0x4c, 0x53, 0x10, 0x30, 0x12, 0x07, 0x0a, 0x03, 0x49,
-	0x4d, 0x50, 0x10, 0x31, 0x12, 0x07, 0x0a, 0x03, 0x49, 0x4e, 0x52, 0x10, 0x32, 0x12, 0x07, 0x0a,
-	0x03, 0x49, 0x51, 0x44, 0x10, 0x33, 0x12, 0x07, 0x0a, 0x03, 0x49, 0x52, 0x52, 0x10, 0x34, 0x12,
-	0x07, 0x0a, 0x03, 0x49, 0x53, 0x4b, 0x10, 0x35, 0x12, 0x07, 0x0a, 0x03, 0x4a, 0x45, 0x50, 0x10,
-	0x36, 0x12, 0x07, 0x0a, 0x03, 0x4a, 0x4d, 0x44, 0x10, 0x37, 0x12, 0x07, 0x0a, 0x03, 0x4a, 0x4f,
-	0x44, 0x10, 0x38, 0x12, 0x07, 0x0a, 0x03, 0x4a, 0x50, 0x59, 0x10, 0x39, 0x12, 0x07, 0x0a, 0x03,
-	0x4b, 0x45, 0x53, 0x10, 0x3a, 0x12, 0x07, 0x0a, 0x03, 0x4b, 0x47, 0x53, 0x10, 0x3b, 0x12, 0x07,
-	0x0a, 0x03, 0x4b, 0x48, 0x52, 0x10, 0x3c, 0x12, 0x07, 0x0a, 0x03, 0x4b, 0x50, 0x57, 0x10, 0x3d,
-	0x12, 0x07, 0x0a, 0x03, 0x4b, 0x52, 0x57, 0x10, 0x3e, 0x12, 0x07, 0x0a, 0x03, 0x4b, 0x57, 0x44,
-	0x10, 0x3f, 0x12, 0x07, 0x0a, 0x03, 0x4b, 0x59, 0x44, 0x10, 0x40, 0x12, 0x07, 0x0a, 0x03, 0x4b,
-	0x5a, 0x54, 0x10, 0x41, 0x12, 0x07, 0x0a, 0x03, 0x4c, 0x41, 0x4b, 0x10, 0x42, 0x12, 0x07, 0x0a,
-	0x03, 0x4c, 0x42, 0x50, 0x10, 0x43, 0x12, 0x07, 0x0a, 0x03, 0x4c, 0x4b, 0x52, 0x10, 0x44, 0x12,
-	0x07, 0x0a, 0x03, 0x4c, 0x52, 0x44, 0x10, 0x45, 0x12, 0x07, 0x0a, 0x03, 0x4c, 0x54, 0x4c, 0x10,
-	0x46, 0x12, 0x07, 0x0a, 0x03, 0x4c, 0x56, 0x4c, 0x10, 0x47, 0x12, 0x07, 0x0a, 0x03, 0x4c, 0x59,
-	0x44, 0x10, 0x48, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x41, 0x44, 0x10, 0x49, 0x12, 0x07, 0x0a, 0x03,
-	0x4d, 0x4b, 0x44, 0x10, 0x4a, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x4e, 0x54, 0x10, 0x4b, 0x12, 0x07,
-	0x0a, 0x03, 0x4d, 0x55, 0x52, 0x10, 0x4c, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x58, 0x4e, 0x10, 0x4d,
-	0x12, 0x07, 0x0a, 0x03, 0x4d, 0x57, 0x4b, 0x10, 0x4e, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x59, 0x52,
-	0x10, 0x4f, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x5a, 0x4e, 0x10, 0x50, 0x12, 0x07, 0x0a, 0x03, 0x4e,
-	0x41, 0x44, 0x10, 0x51, 0x12, 0x07, 0x0a, 0x03, 0x4e, 0x47, 0x4e, 0x10, 0x52, 0x12, 0x07, 0x0a,
-	0x03, 0x4e, 0x49, 0x4f, 0x10, 0x53, 0x12, 0x07, 0x0a, 0x03, 0x4e, 0x4f, 0x4b, 0x10, 0x54, 0x12,
-	0x07, 0x0a, 0x03, 0x4e, 0x50, 0x52, 0x10, 0x55, 0x12, 0x07, 0x0a, 0x03, 0x4e, 0x5a, 0x44, 0x10,
-	0x56, 0x12, 0x07, 0x0a, 0x03, 0x4f, 0x4d, 0x52, 0x10, 0x57, 0x12, 0x07, 0x0a, 0x03, 0x50, 0x41,
-	0x42, 0x10, 0x58, 0x12, 0x07, 0x0a, 0x03, 0x50, 0x45, 0x4e, 0x10, 0x59, 0x12, 0x07, 0x0a, 0x03,
-	0x50, 0x48, 0x50, 0x10, 0x5a, 0x12, 0x07, 0x0a, 0x03, 0x50, 0x4b, 0x52, 0x10, 0x5b, 0x12, 0x07,
-	0x0a, 0x03, 0x50, 0x4c, 0x4e, 0x10, 0x5c, 0x12, 0x07, 0x0a, 0x03, 0x50, 0x59, 0x47, 0x10, 0x5d,
-	0x12, 0x07, 0x0a, 0x03, 0x51, 0x41, 0x52, 0x10, 0x5e, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x4f, 0x4e,
-	0x10, 0x5f, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x53, 0x44, 0x10, 0x60, 0x12, 0x07, 0x0a, 0x03, 0x52,
-	0x55, 0x42, 0x10, 0x61, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x55, 0x52, 0x10, 0x62, 0x12, 0x07, 0x0a,
-	0x03, 0x53, 0x41, 0x52, 0x10, 0x63, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x42, 0x44, 0x10, 0x64, 0x12,
-	0x07, 0x0a, 0x03, 0x53, 0x43, 0x52, 0x10, 0x65, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x45, 0x4b, 0x10,
-	0x66, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x47, 0x44, 0x10, 0x67, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x48,
-	0x50, 0x10, 0x68, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x4f, 0x53, 0x10, 0x69, 0x12, 0x07, 0x0a, 0x03,
-	0x53, 0x52, 0x44, 0x10, 0x6a, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x56, 0x43, 0x10, 0x6b, 0x12, 0x07,
-	0x0a, 0x03, 0x53, 0x59, 0x50, 0x10, 0x6c, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x48, 0x42, 0x10, 0x6d,
-	0x12, 0x07, 0x0a, 0x03, 0x54, 0x4e, 0x44, 0x10, 0x6e, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x52, 0x4c,
-	0x10, 0x6f, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x52, 0x59, 0x10, 0x70, 0x12, 0x07, 0x0a, 0x03, 0x54,
-	0x54, 0x44, 0x10, 0x71, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x57, 0x44, 0x10, 0x72, 0x12, 0x07, 0x0a,
-	0x03, 0x54, 0x5a, 0x53, 0x10, 0x73, 0x12, 0x07, 0x0a, 0x03, 0x55, 0x41, 0x48, 0x10, 0x74, 0x12,
-	0x07, 0x0a, 0x03, 0x55, 0x47, 0x58, 0x10, 0x75, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x45, 0x44, 0x10,
-	0x76, 0x12, 0x07, 0x0a, 0x03, 0x55, 0x59, 0x55, 0x10, 0x77, 0x12, 0x07, 0x0a, 0x03, 0x55, 0x5a,
-	0x53, 0x10, 0x78, 0x12, 0x07, 0x0a, 0x03, 0x56, 0x45, 0x46, 0x10, 0x79, 0x12, 0x07, 0x0a, 0x03,
-	0x56, 0x4e, 0x44, 0x10, 0x7a, 0x12, 0x07, 0x0a, 0x03, 0x58, 0x43, 0x44, 0x10, 0x7b, 0x12, 0x07,
-	0x0a, 0x03, 0x59, 0x45, 0x52, 0x10, 0x7c, 0x12, 0x07, 0x0a, 0x03, 0x5a, 0x41, 0x52, 0x10, 0x7d,
-	0x12, 0x07, 0x0a, 0x03, 0x5a, 0x4d, 0x57, 0x10, 0x7e, 0x12, 0x07, 0x0a, 0x03, 0x5a, 0x57, 0x44,
-	0x10, 0x7f, 0x12, 0x08, 0x0a, 0x03, 0x55, 0x53, 0x44, 0x10, 0x80, 0x01, 0x32, 0xf4, 0x01, 0x0a,
-	0x0e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12,
-	0x4c, 0x0a, 0x09, 0x4e, 0x65, 0x77, 0x43, 0x68, 0x61, 0x72, 0x67, 0x65, 0x12, 0x18, 0x2e, 0x70,
-	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x72, 0x67, 0x65, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
-	0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x72, 0x67, 0x65, 0x22, 0x12, 0x82, 0xd3, 0xe4, 0x93, 0x02,
-	0x0c, 0x22, 0x07, 0x2f, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x3a, 0x01, 0x2a, 0x12, 0x4f, 0x0a,
-	0x0c, 0x52, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x43, 0x68, 0x61, 0x72, 0x67, 0x65, 0x12, 0x18, 0x2e,
-	0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x66, 0x75, 0x6e, 0x64,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e,
-	0x74, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x72, 0x67, 0x65, 0x22, 0x12, 0x82, 0xd3, 0xe4, 0x93,
-	0x02, 0x0c, 0x22, 0x07, 0x2f, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x3a, 0x01, 0x2a, 0x12, 0x43,
-	0x0a, 0x03, 0x47, 0x65, 0x74, 0x12, 0x15, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x70,
-	0x62, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x70,
-	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x72, 0x67, 0x65, 0x22,
-	0x12, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0c, 0x12, 0x07, 0x2f, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65,
-	0x3a, 0x01, 0x2a, 0x42, 0x3f, 0x5a, 0x3d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x19, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0xe6, 0x07, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x19, 0x0a, 0x07, 0x75, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x64, 0x18, 0xe7, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x75, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x64, 0x22, 0xb3, 0x01, 0x0a, 0x06, 0x52, 0x65, 0x66, 0x75, 0x6e, 0x64,
+	0x12, 0x22, 0x0a, 0x0c, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x41, 0x6d,
+	0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2a, 0x0a, 0x10, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72,
+	0x52, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10,
+	0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x52, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x49, 0x64,
+	0x12, 0x2f, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x17, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x66,
+	0x75, 0x6e, 0x64, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0xd5, 0x01, 0x0a, 0x04,
+	0x43, 0x61, 0x72, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x20, 0x0a, 0x0b,
+	0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x4d, 0x6f, 0x6e, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x4d, 0x6f, 0x6e, 0x74, 0x68, 0x12, 0x1e,
+	0x0a, 0x0a, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x59, 0x65, 0x61, 0x72, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x59, 0x65, 0x61, 0x72, 0x12, 0x1c,
+	0x0a, 0x09, 0x46, 0x69, 0x72, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x46, 0x69, 0x72, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08,
+	0x4c, 0x61, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x4c, 0x61, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x43, 0x56, 0x43, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x43, 0x56, 0x43, 0x12, 0x27, 0x0a, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x70, 0x62, 0x2e, 0x43, 0x61, 0x72, 0x64, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x2a, 0x58, 0x0a, 0x11, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x50, 0x72,
+	0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x49, 0x64, 0x12, 0x15, 0x0a, 0x11, 0x50, 0x52, 0x4f, 0x56,
+	0x49, 0x44, 0x45, 0x52, 0x5f, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x10, 0x00, 0x12,
+	0x0a, 0x0a, 0x06, 0x41, 0x6c, 0x69, 0x50, 0x61, 0x79, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x50,
+	0x61, 0x79, 0x70, 0x61, 0x6c, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x57, 0x65, 0x43, 0x68, 0x61,
+	0x74, 0x10, 0x03, 0x12, 0x08, 0x0a, 0x04, 0x4d, 0x6f, 0x63, 0x6b, 0x10, 0x04, 0x2a, 0x26, 0x0a,
+	0x0c, 0x43, 0x68, 0x61, 0x72, 0x67, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x08, 0x0a,
+	0x04, 0x50, 0x61, 0x69, 0x64, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x52, 0x65, 0x66, 0x75, 0x6e,
+	0x64, 0x65, 0x64, 0x10, 0x01, 0x2a, 0x53, 0x0a, 0x0c, 0x52, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x52,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x0c, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x6c,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x46, 0x72, 0x61, 0x75, 0x64,
+	0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x44, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x10,
+	0x02, 0x12, 0x17, 0x0a, 0x13, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x42, 0x79,
+	0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x65, 0x72, 0x10, 0x03, 0x2a, 0x73, 0x0a, 0x08, 0x43, 0x61,
+	0x72, 0x64, 0x54, 0x79, 0x70, 0x65, 0x12, 0x11, 0x0a, 0x0d, 0x43, 0x41, 0x52, 0x44, 0x5f, 0x52,
+	0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x4d, 0x61, 0x73,
+	0x74, 0x65, 0x72, 0x63, 0x61, 0x72, 0x64, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x56, 0x69, 0x73,
+	0x61, 0x10, 0x02, 0x12, 0x13, 0x0a, 0x0f, 0x41, 0x6d, 0x65, 0x72, 0x69, 0x63, 0x61, 0x6e, 0x45,
+	0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x10, 0x03, 0x12, 0x07, 0x0a, 0x03, 0x4a, 0x43, 0x42, 0x10,
+	0x04, 0x12, 0x0c, 0x0a, 0x08, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x10, 0x05, 0x12,
+	0x0e, 0x0a, 0x0a, 0x44, 0x69, 0x6e, 0x65, 0x72, 0x73, 0x43, 0x6c, 0x75, 0x62, 0x10, 0x06, 0x2a,
+	0x9d, 0x09, 0x0a, 0x08, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x10, 0x0a, 0x0c,
+	0x43, 0x55, 0x52, 0x5f, 0x52, 0x45, 0x53, 0x45, 0x52, 0x56, 0x45, 0x44, 0x10, 0x00, 0x12, 0x07,
+	0x0a, 0x03, 0x41, 0x46, 0x4e, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x4c, 0x4c, 0x10, 0x02,
+	0x12, 0x07, 0x0a, 0x03, 0x41, 0x4d, 0x44, 0x10, 0x03, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x4e, 0x47,
+	0x10, 0x04, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x52, 0x53, 0x10, 0x05, 0x12, 0x07, 0x0a, 0x03, 0x41,
+	0x55, 0x44, 0x10, 0x06, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x57, 0x47, 0x10, 0x07, 0x12, 0x07, 0x0a,
+	0x03, 0x41, 0x5a, 0x4e, 0x10, 0x08, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x41, 0x4d, 0x10, 0x09, 0x12,
+	0x07, 0x0a, 0x03, 0x42, 0x42, 0x44, 0x10, 0x0a, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x47, 0x4e, 0x10,
+	0x0b, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x48, 0x44, 0x10, 0x0c, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x4d,
+	0x44, 0x10, 0x0d, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x4e, 0x44, 0x10, 0x0e, 0x12, 0x07, 0x0a, 0x03,
+	0x42, 0x4f, 0x42, 0x10, 0x0f, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x52, 0x4c, 0x10, 0x10, 0x12, 0x07,
+	0x0a, 0x03, 0x42, 0x53, 0x44, 0x10, 0x11, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x57, 0x50, 0x10, 0x12,
+	0x12, 0x07, 0x0a, 0x03, 0x42, 0x59, 0x4e, 0x10, 0x13, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x59, 0x52,
+	0x10, 0x14, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x5a, 0x44, 0x10, 0x15, 0x12, 0x07, 0x0a, 0x03, 0x43,
+	0x41, 0x44, 0x10, 0x16, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x4c, 0x50, 0x10, 0x17, 0x12, 0x07, 0x0a,
+	0x03, 0x43, 0x4e, 0x59, 0x10, 0x18, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x4f, 0x50, 0x10, 0x19, 0x12,
+	0x07, 0x0a, 0x03, 0x43, 0x52, 0x43, 0x10, 0x1a, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x55, 0x50, 0x10,
+	0x1b, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x5a, 0x4b, 0x10, 0x1c, 0x12, 0x07, 0x0a, 0x03, 0x44, 0x4b,
+	0x4b, 0x10, 0x1d, 0x12, 0x07, 0x0a, 0x03, 0x44, 0x4f, 0x50, 0x10, 0x1e, 0x12, 0x07, 0x0a, 0x03,
+	0x44, 0x5a, 0x44, 0x10, 0x1f, 0x12, 0x07, 0x0a, 0x03, 0x45, 0x45, 0x4b, 0x10, 0x20, 0x12, 0x07,
+	0x0a, 0x03, 0x45, 0x47, 0x50, 0x10, 0x21, 0x12, 0x07, 0x0a, 0x03, 0x45, 0x55, 0x52, 0x10, 0x22,
+	0x12, 0x07, 0x0a, 0x03, 0x46, 0x4a, 0x44, 0x10, 0x23, 0x12, 0x07, 0x0a, 0x03, 0x46, 0x4b, 0x50,
+	0x10, 0x24, 0x12, 0x07, 0x0a, 0x03, 0x47, 0x42, 0x50, 0x10, 0x25, 0x12, 0x07, 0x0a, 0x03, 0x47,
+	0x47, 0x50, 0x10, 0x26, 0x12, 0x07, 0x0a, 0x03, 0x47, 0x48, 0x43, 0x10, 0x27, 0x12, 0x07, 0x0a,
+	0x03, 0x47, 0x49, 0x50, 0x10, 0x28, 0x12, 0x07, 0x0a, 0x03, 0x47, 0x54, 0x51, 0x10, 0x29, 0x12,
+	0x07, 0x0a, 0x03, 0x47, 0x59, 0x44, 0x10, 0x2a, 0x12, 0x07, 0x0a, 0x03, 0x48, 0x4b, 0x44, 0x10,
+	0x2b, 0x12, 0x07, 0x0a, 0x03, 0x48, 0x4e, 0x4c, 0x10, 0x2c, 0x12, 0x07, 0x0a, 0x03, 0x48, 0x52,
+	0x4b, 0x10, 0x2d, 0x12, 0x07, 0x0a, 0x03, 0x48, 0x55, 0x46, 0x10, 0x2e, 0x12, 0x07, 0x0a, 0x03,
+	0x49, 0x44, 0x52, 0x10, 0x2f, 0x12, 0x07, 0x0a, 0x03, 0x49, 0x4c, 0x53, 0x10, 0x30, 0x12, 0x07,
+	0x0a, 0x03, 0x49, 0x4d, 0x50, 0x10, 0x31, 0x12, 0x07, 0x0a, 0x03, 0x49, 0x4e, 0x52, 0x10, 0x32,
+	0x12, 0x07, 0x0a, 0x03, 0x49, 0x51, 0x44, 0x10, 0x33, 0x12, 0x07, 0x0a, 0x03, 0x49, 0x52, 0x52,
+	0x10, 0x34, 0x12, 0x07, 0x0a, 0x03, 0x49, 0x53, 0x4b, 0x10, 0x35, 0x12, 0x07, 0x0a, 0x03, 0x4a,
+	0x45, 0x50, 0x10, 0x36, 0x12, 0x07, 0x0a, 0x03, 0x4a, 0x4d, 0x44, 0x10, 0x37, 0x12, 0x07, 0x0a,
+	0x03, 0x4a, 0x4f, 0x44, 0x10, 0x38, 0x12, 0x07, 0x0a, 0x03, 0x4a, 0x50, 0x59, 0x10, 0x39, 0x12,
+	0x07, 0x0a, 0x03, 0x4b, 0x45, 0x53, 0x10, 0x3a, 0x12, 0x07, 0x0a, 0x03, 0x4b, 0x47, 0x53, 0x10,
+	0x3b, 0x12, 0x07, 0x0a, 0x03, 0x4b, 0x48, 0x52, 0x10, 0x3c, 0x12, 0x07, 0x0a, 0x03, 0x4b, 0x50,
+	0x57, 0x10, 0x3d, 0x12, 0x07, 0x0a, 0x03, 0x4b, 0x52, 0x57, 0x10, 0x3e, 0x12, 0x07, 0x0a, 0x03,
+	0x4b, 0x57, 0x44, 0x10, 0x3f, 0x12, 0x07, 0x0a, 0x03, 0x4b, 0x59, 0x44, 0x10, 0x40, 0x12, 0x07,
+	0x0a, 0x03, 0x4b, 0x5a, 0x54, 0x10, 0x41, 0x12, 0x07, 0x0a, 0x03, 0x4c, 0x41, 0x4b, 0x10, 0x42,
+	0x12, 0x07, 0x0a, 0x03, 0x4c, 0x42, 0x50, 0x10, 0x43, 0x12, 0x07, 0x0a, 0x03, 0x4c, 0x4b, 0x52,
+	0x10, 0x44, 0x12, 0x07, 0x0a, 0x03, 0x4c, 0x52, 0x44, 0x10, 0x45, 0x12, 0x07, 0x0a, 0x03, 0x4c,
+	0x54, 0x4c, 0x10, 0x46, 0x12, 0x07, 0x0a, 0x03, 0x4c, 0x56, 0x4c, 0x10, 0x47, 0x12, 0x07, 0x0a,
+	0x03, 0x4c, 0x59, 0x44, 0x10, 0x48, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x41, 0x44, 0x10, 0x49, 0x12,
+	0x07, 0x0a, 0x03, 0x4d, 0x4b, 0x44, 0x10, 0x4a, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x4e, 0x54, 0x10,
+	0x4b, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x55, 0x52, 0x10, 0x4c, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x58,
+	0x4e, 0x10, 0x4d, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x57, 0x4b, 0x10, 0x4e, 0x12, 0x07, 0x0a, 0x03,
+	0x4d, 0x59, 0x52, 0x10, 0x4f, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x5a, 0x4e, 0x10, 0x50, 0x12, 0x07,
+	0x0a, 0x03, 0x4e, 0x41, 0x44, 0x10, 0x51, 0x12, 0x07, 0x0a, 0x03, 0x4e, 0x47, 0x4e, 0x10, 0x52,
+	0x12, 0x07, 0x0a, 0x03, 0x4e, 0x49, 0x4f, 0x10, 0x53, 0x12, 0x07, 0x0a, 0x03, 0x4e, 0x4f, 0x4b,
+	0x10, 0x54, 0x12, 0x07, 0x0a, 0x03, 0x4e, 0x50, 0x52, 0x10, 0x55, 0x12, 0x07, 0x0a, 0x03, 0x4e,
+	0x5a, 0x44, 0x10, 0x56, 0x12, 0x07, 0x0a, 0x03, 0x4f, 0x4d, 0x52, 0x10, 0x57, 0x12, 0x07, 0x0a,
+	0x03, 0x50, 0x41, 0x42, 0x10, 0x58, 0x12, 0x07, 0x0a, 0x03, 0x50, 0x45, 0x4e, 0x10, 0x59, 0x12,
+	0x07, 0x0a, 0x03, 0x50, 0x48, 0x50, 0x10, 0x5a, 0x12, 0x07, 0x0a, 0x03, 0x50, 0x4b, 0x52, 0x10,
+	0x5b, 0x12, 0x07, 0x0a, 0x03, 0x50, 0x4c, 0x4e, 0x10, 0x5c, 0x12, 0x07, 0x0a, 0x03, 0x50, 0x59,
+	0x47, 0x10, 0x5d, 0x12, 0x07, 0x0a, 0x03, 0x51, 0x41, 0x52, 0x10, 0x5e, 0x12, 0x07, 0x0a, 0x03,
+	0x52, 0x4f, 0x4e, 0x10, 0x5f, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x53, 0x44, 0x10, 0x60, 0x12, 0x07,
+	0x0a, 0x03, 0x52, 0x55, 0x42, 0x10, 0x61, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x55, 0x52, 0x10, 0x62,
+	0x12, 0x07, 0x0a, 0x03, 0x53, 0x41, 0x52, 0x10, 0x63, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x42, 0x44,
+	0x10, 0x64, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x43, 0x52, 0x10, 0x65, 0x12, 0x07, 0x0a, 0x03, 0x53,
+	0x45, 0x4b, 0x10, 0x66, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x47, 0x44, 0x10, 0x67, 0x12, 0x07, 0x0a,
+	0x03, 0x53, 0x48, 0x50, 0x10, 0x68, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x4f, 0x53, 0x10, 0x69, 0x12,
+	0x07, 0x0a, 0x03, 0x53, 0x52, 0x44, 0x10, 0x6a, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x56, 0x43, 0x10,
+	0x6b, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x59, 0x50, 0x10, 0x6c, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x48,
+	0x42, 0x10, 0x6d, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x4e, 0x44, 0x10, 0x6e, 0x12, 0x07, 0x0a, 0x03,
+	0x54, 0x52, 0x4c, 0x10, 0x6f, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x52, 0x59, 0x10, 0x70, 0x12, 0x07,
+	0x0a, 0x03, 0x54, 0x54, 0x44, 0x10, 0x71, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x57, 0x44, 0x10, 0x72,
+	0x12, 0x07, 0x0a, 0x03, 0x54, 0x5a, 0x53, 0x10, 0x73, 0x12, 0x07, 0x0a, 0x03, 0x55, 0x41, 0x48,
+	0x10, 0x74, 0x12, 0x07, 0x0a, 0x03, 0x55, 0x47, 0x58, 0x10, 0x75, 0x12, 0x07, 0x0a, 0x03, 0x41,
+	0x45, 0x44, 0x10, 0x76, 0x12, 0x07, 0x0a, 0x03, 0x55, 0x59, 0x55, 0x10, 0x77, 0x12, 0x07, 0x0a,
+	0x03, 0x55, 0x5a, 0x53, 0x10, 0x78, 0x12, 0x07, 0x0a, 0x03, 0x56, 0x45, 0x46, 0x10, 0x79, 0x12,
+	0x07, 0x0a, 0x03, 0x56, 0x4e, 0x44, 0x10, 0x7a, 0x12, 0x07, 0x0a, 0x03, 0x58, 0x43, 0x44, 0x10,
+	0x7b, 0x12, 0x07, 0x0a, 0x03, 0x59, 0x45, 0x52, 0x10, 0x7c, 0x12, 0x07, 0x0a, 0x03, 0x5a, 0x41,
+	0x52, 0x10, 0x7d, 0x12, 0x07, 0x0a, 0x03, 0x5a, 0x4d, 0x57, 0x10, 0x7e, 0x12, 0x07, 0x0a, 0x03,
+	0x5a, 0x57, 0x44, 0x10, 0x7f, 0x12, 0x08, 0x0a, 0x03, 0x55, 0x53, 0x44, 0x10, 0x80, 0x01, 0x32,
+	0xf1, 0x01, 0x0a, 0x0e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x4c, 0x0a, 0x09, 0x4e, 0x65, 0x77, 0x43, 0x68, 0x61, 0x72, 0x67, 0x65, 0x12,
+	0x18, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x72,
+	0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x70, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x72, 0x67, 0x65, 0x22, 0x12, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x0c, 0x22, 0x07, 0x2f, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x3a, 0x01, 0x2a,
+	0x12, 0x4f, 0x0a, 0x0c, 0x52, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x43, 0x68, 0x61, 0x72, 0x67, 0x65,
+	0x12, 0x18, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x66,
+	0x75, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x70, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x72, 0x67, 0x65, 0x22, 0x12, 0x82,
+	0xd3, 0xe4, 0x93, 0x02, 0x0c, 0x22, 0x07, 0x2f, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x3a, 0x01,
+	0x2a, 0x12, 0x40, 0x0a, 0x03, 0x47, 0x65, 0x74, 0x12, 0x15, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x70, 0x62, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x11, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x72,
+	0x67, 0x65, 0x22, 0x0f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x09, 0x12, 0x07, 0x2f, 0x63, 0x68, 0x61,
+	0x72, 0x67, 0x65, 0x42, 0x3f, 0x5a, 0x3d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
 	0x6d, 0x2f, 0x78, 0x69, 0x64, 0x6f, 0x6e, 0x67, 0x63, 0x2f, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x64,
 	0x62, 0x5f, 0x65, 0x62, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b, 0x2f, 0x6d, 0x6f, 0x64,
 	0x65, 0x6c, 0x2f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x2f, 0x70, 0x61, 0x79, 0x6d, 0x65,
@@ -1404,19 +1439,20 @@ var file_paymentpb_payment_proto_depIdxs = []int32{
 	2,  // 4: paymentpb.RefundRequest.reason:type_name -> paymentpb.RefundReason
 	9,  // 5: paymentpb.Charge.refunds:type_name -> paymentpb.Refund
 	4,  // 6: paymentpb.Charge.currency:type_name -> paymentpb.Currency
-	2,  // 7: paymentpb.Refund.reason:type_name -> paymentpb.RefundReason
-	3,  // 8: paymentpb.Card.type:type_name -> paymentpb.CardType
-	5,  // 9: paymentpb.PaymentService.NewCharge:input_type -> paymentpb.ChargeRequest
-	6,  // 10: paymentpb.PaymentService.RefundCharge:input_type -> paymentpb.RefundRequest
-	7,  // 11: paymentpb.PaymentService.Get:input_type -> paymentpb.GetRequest
-	8,  // 12: paymentpb.PaymentService.NewCharge:output_type -> paymentpb.Charge
-	8,  // 13: paymentpb.PaymentService.RefundCharge:output_type -> paymentpb.Charge
-	8,  // 14: paymentpb.PaymentService.Get:output_type -> paymentpb.Charge
-	12, // [12:15] is the sub-list for method output_type
-	9,  // [9:12] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	0,  // 7: paymentpb.Charge.paymentProviderId:type_name -> paymentpb.PaymentProviderId
+	2,  // 8: paymentpb.Refund.reason:type_name -> paymentpb.RefundReason
+	3,  // 9: paymentpb.Card.type:type_name -> paymentpb.CardType
+	5,  // 10: paymentpb.PaymentService.NewCharge:input_type -> paymentpb.ChargeRequest
+	6,  // 11: paymentpb.PaymentService.RefundCharge:input_type -> paymentpb.RefundRequest
+	7,  // 12: paymentpb.PaymentService.Get:input_type -> paymentpb.GetRequest
+	8,  // 13: paymentpb.PaymentService.NewCharge:output_type -> paymentpb.Charge
+	8,  // 14: paymentpb.PaymentService.RefundCharge:output_type -> paymentpb.Charge
+	8,  // 15: paymentpb.PaymentService.Get:output_type -> paymentpb.Charge
+	13, // [13:16] is the sub-list for method output_type
+	10, // [10:13] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_paymentpb_payment_proto_init() }
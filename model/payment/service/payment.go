@@ -19,46 +19,87 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/uuid"
+	"github.com/mitchellh/mapstructure"
 	log "github.com/sirupsen/logrus"
+	"github.com/xidongc-wish/mgo/bson"
 	"github.com/xidongc/mongo_ebenchmark/model/payment/paymentpb"
 	"github.com/xidongc/mongo_ebenchmark/model/payment/service/provider"
 	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"github.com/xidongc/mongo_ebenchmark/pkg/idempotency"
 	"github.com/xidongc/mongo_ebenchmark/pkg/proxy"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+	"time"
 )
 
 const ns = "payment"
 
+// default time a pending refund is held before a retry of its refund id
+// takes it over
+const defaultRefundLease = time.Minute
+
+// Payment Service, charges go to providers of Providers, AliPay and a
+// mock without latency and failures are used if it is not set. retried
+// charges with the same idempotency key are replayed from Idempotency
 type Service struct {
-	Storage   proxy.Client
-	Amplifier cfg.Amplifier
+	Storage     proxy.Storage
+	Amplifier   cfg.Amplifier
+	Providers   *Registry
+	Idempotency *idempotency.Store
+	RefundLease time.Duration
 }
 
-// New Charge
+// New Charge, a retry with the same idempotency key returns the original
+// charge without charging again
 func (s Service) NewCharge(ctx context.Context, req *paymentpb.ChargeRequest) (*paymentpb.Charge, error) {
-	providerId := req.GetPaymentProviderId()
-	var provide Provider
+	response, err := s.Idempotency.Do(ctx, "payment.NewCharge", req, &paymentpb.Charge{}, func() (proto.Message, error) {
+		return s.newCharge(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.(*paymentpb.Charge), nil
+}
 
-	// TODO add more
-	switch providerId {
-	case paymentpb.PaymentProviderId_AliPay:
-		provide = &provider.AliPay{}
-	default:
-		provide = &provider.AliPay{}
+// newCharge charges by provider and stores the charge, card type is
+// validated against supported cards of provider, charge fails over to
+// secondary provider on errors other than declined or duplicate charge,
+// and records provider it is charged by
+func (s Service) newCharge(ctx context.Context, req *paymentpb.ChargeRequest) (*paymentpb.Charge, error) {
+	p, err := s.registry().Provider(req.GetPaymentProviderId())
+	if err != nil {
+		return nil, err
+	}
+	cardType := req.GetCard().GetType()
+	if !supportsCard(p, cardType) {
+		return nil, status.Errorf(codes.InvalidArgument, "card type %s is not supported by %s", cardType, p.ProviderId())
 	}
 
-	charge, err := provide.Charge(req)
+	charge, err := p.Charge(req)
+	if err != nil && canFailover(err) {
+		if secondary, ok := s.registry().Secondary(p.ProviderId()); ok && supportsCard(secondary, cardType) {
+			log.Warningf("charge failed with: %s, fail over to %s", err, secondary.ProviderId())
+			p = secondary
+			charge, err = p.Charge(req)
+		}
+	}
 
 	if err != nil {
-		log.Warning("do refund")
-		// TODO do refund
+		log.Warningf("charge failed with: %s", err)
+		return nil, providerStatus("charge", err)
 	}
 
 	if charge == nil {
-		return nil, errors.New("error")
+		return nil, status.Error(codes.Internal, "provider returned empty charge")
 	}
+	charge.PaymentProviderId = p.ProviderId()
 
 	var docs []interface{}
-	docs = append(docs, *charge)
+	docs = append(docs, charge)
 
 	param := &proxy.InsertParam{
 		Docs: docs,
@@ -67,23 +108,310 @@ func (s Service) NewCharge(ctx context.Context, req *paymentpb.ChargeRequest) (*
 
 	if err := s.Storage.Insert(ctx, param); err != nil {
 		log.Error(err)
-		return nil, err
+		return nil, proxy.ToStatus(err)
 	}
 	return charge, nil
 }
 
-// Refund Charge
+// Refund Charge, amount is validated against refundable amount of the
+// charge and reserved before provider is called: refund amount is
+// increased and a pending refund recorded in one conditional update on
+// current refund amount, so concurrent refunds never exceed charge amount
+// nor reach provider twice. the pending refund is completed once provider
+// refunded, or released if provider failed. a retry with the same refund
+// id returns the charge without refunding again, or takes over a pending
+// refund older than RefundLease, left by a lost request, and sends it to
+// provider again, providers refund a refund id once
 func (s Service) RefundCharge(ctx context.Context, req *paymentpb.RefundRequest) (charge *paymentpb.Charge, err error) {
-	return
+	doc, err := s.find(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if charge, err = decode(doc); err != nil {
+		return nil, err
+	}
+
+	refundId := req.GetRefundId()
+	if refundId == "" {
+		refundId = uuid.New().String()
+	}
+	if strings.ContainsAny(refundId, ".$") {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid refund id %s", refundId)
+	}
+	p, err := s.registry().Provider(charge.GetPaymentProviderId())
+	if err != nil {
+		return nil, err
+	}
+
+	var amount uint64
+	for reserved := false; !reserved; {
+		if hasRefund(charge, refundId) {
+			return charge, nil
+		}
+		if reservation, ok := pending(doc)[refundId]; ok {
+			if time.Since(time.Unix(0, reservation.Reserved)) < s.refundLease() {
+				return nil, status.Errorf(codes.Aborted, "refund %s of charge %s is in progress", refundId, charge.GetId())
+			}
+			amount = reservation.Amount
+			if reserved, err = s.renewRefund(ctx, charge.GetId(), refundId, reservation.Reserved); err != nil || reserved {
+				break
+			}
+		} else {
+			if amount = req.GetAmount(); amount == 0 {
+				amount = charge.GetChargeAmount() - charge.GetRefundAmount()
+			}
+			if err = refundable(charge, amount); err != nil {
+				return nil, err
+			}
+			if reserved, err = s.reserveRefund(ctx, charge, refundId, amount); err != nil || reserved {
+				break
+			}
+		}
+
+		// a concurrent refund won, validate against refunds of it
+		if doc, err = s.find(ctx, req.GetId()); err != nil {
+			return nil, err
+		}
+		if charge, err = decode(doc); err != nil {
+			return nil, err
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	refund, err := p.Refund(charge.GetId(), refundId, amount, charge.GetCurrency(), req.GetReason())
+	if err == nil && refund == nil {
+		err = errors.New("provider returned empty refund")
+	}
+	if err != nil {
+		log.Error(err)
+		if releaseErr := s.releaseRefund(ctx, charge.GetId(), refundId, amount); releaseErr != nil {
+			log.Errorf("release refund %s of charge %s error with: %s", refundId, charge.GetId(), releaseErr)
+		}
+		return nil, providerStatus("refund", err)
+	}
+	refund.Id = refundId
+	refund.Reason = req.GetReason()
+	if refund.Created == 0 {
+		refund.Created = time.Now().UnixNano()
+	}
+	return s.completeRefund(ctx, charge.GetId(), refund)
+}
+
+// reserveRefund adds amount to refund amount of charge and records it
+// as pending under refund id, reserved is false if refund amount was
+// changed by a concurrent refund since charge was read
+func (s Service) reserveRefund(ctx context.Context, charge *paymentpb.Charge, refundId string, amount uint64) (reserved bool, err error) {
+	param := &proxy.UpdateParam{
+		Filter: bson.M{
+			"id":                    charge.GetId(),
+			"refundamount":          charge.GetRefundAmount(),
+			pendingRefund(refundId): bson.M{"$exists": false},
+		},
+		Update: bson.M{
+			"$inc": bson.M{"refundamount": amount},
+			"$set": bson.M{
+				pendingRefund(refundId): bson.M{"amount": amount, "reserved": time.Now().UnixNano()},
+				"refunded":              charge.GetRefundAmount()+amount == charge.GetChargeAmount(),
+				"updated":               time.Now().UnixNano(),
+			},
+		},
+		Amp: s.Amplifier,
+	}
+	changeInfo, err := s.Storage.Update(ctx, param)
+	if err != nil {
+		log.Error(err)
+		return false, proxy.ToStatus(err)
+	}
+	return changeInfo.GetMatched() > 0, nil
+}
+
+// renewRefund takes over pending refund reserved at given time, taken is
+// false if a concurrent retry took it over or it is no longer pending
+func (s Service) renewRefund(ctx context.Context, chargeId string, refundId string, reserved int64) (taken bool, err error) {
+	param := &proxy.UpdateParam{
+		Filter: bson.M{"id": chargeId, pendingRefund(refundId) + ".reserved": reserved},
+		Update: bson.M{"$set": bson.M{pendingRefund(refundId) + ".reserved": time.Now().UnixNano()}},
+		Amp:    s.Amplifier,
+	}
+	changeInfo, err := s.Storage.Update(ctx, param)
+	if err != nil {
+		log.Error(err)
+		return false, proxy.ToStatus(err)
+	}
+	return changeInfo.GetMatched() > 0, nil
+}
+
+// completeRefund records refund done by provider in place of pending one
+func (s Service) completeRefund(ctx context.Context, chargeId string, refund *paymentpb.Refund) (*paymentpb.Charge, error) {
+	param := &proxy.FindModifyParam{
+		Filter: bson.M{"id": chargeId, pendingRefund(refund.GetId()): bson.M{"$exists": true}},
+		Desired: bson.M{
+			"$unset": bson.M{pendingRefund(refund.GetId()): ""},
+			"$push":  bson.M{"refunds": refund},
+			"$set":   bson.M{"updated": refund.GetCreated()},
+		},
+		Mode: proxy.FindAndUpdate,
+		Amp:  s.Amplifier,
+	}
+	result, err := s.Storage.FindAndModify(ctx, param)
+	if err != nil {
+		log.Error(err)
+		return nil, proxy.ToStatus(err)
+	}
+	return decode(result)
+}
+
+// releaseRefund gives back amount reserved by pending refund
+func (s Service) releaseRefund(ctx context.Context, chargeId string, refundId string, amount uint64) error {
+	param := &proxy.UpdateParam{
+		Filter: bson.M{"id": chargeId, pendingRefund(refundId): bson.M{"$exists": true}},
+		Update: bson.M{
+			"$inc":   bson.M{"refundamount": -int64(amount)},
+			"$unset": bson.M{pendingRefund(refundId): ""},
+			"$set":   bson.M{"refunded": false, "updated": time.Now().UnixNano()},
+		},
+		Amp: s.Amplifier,
+	}
+	_, err := s.Storage.Update(ctx, param)
+	return err
 }
 
 // Get Charge
 func (s Service) Get(ctx context.Context, req *paymentpb.GetRequest) (charge *paymentpb.Charge, err error) {
+	doc, err := s.find(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return decode(doc)
+}
+
+// find returns stored document of charge
+func (s Service) find(ctx context.Context, id string) (doc bson.M, err error) {
+	param := &proxy.QueryParam{
+		Filter:  bson.M{"id": id},
+		FindOne: true,
+		Amp:     s.Amplifier,
+	}
+
+	results, err := s.Storage.Find(ctx, param)
+
+	if err != nil {
+		log.Error(err)
+		return nil, proxy.ToStatus(err)
+	} else if len(results) > 1 {
+		return nil, status.Errorf(codes.FailedPrecondition, "duplicate charge %s", id)
+	} else if len(results) == 0 {
+		return nil, proxy.ToStatus(proxy.ErrNotFound)
+	}
+	return results[0], nil
+}
+
+// registry of AliPay and a mock without latency and failures
+var defaultRegistry = func() *Registry {
+	mock, _ := provider.NewMock(provider.MockOptions{})
+	registry, _ := NewRegistry(&cfg.PaymentConfig{PaymentProviders: []string{"AliPay", "Mock"}}, &provider.AliPay{}, mock)
+	return registry
+}()
+
+// registry returns Providers, or default registry if it is not set
+func (s Service) registry() *Registry {
+	if s.Providers != nil {
+		return s.Providers
+	}
+	return defaultRegistry
+}
+
+// canFailover reports whether charge error may succeed on another
+// provider, declined and duplicate charges would not
+func canFailover(err error) bool {
+	return !errors.Is(err, provider.ErrDeclined) && !errors.Is(err, provider.ErrCardNotSupported) && !errors.Is(err, provider.ErrDuplicate)
+}
+
+// providerStatus converts provider error into grpc status error
+func providerStatus(op string, err error) error {
+	code := codes.Aborted
+	switch {
+	case errors.Is(err, provider.ErrDeclined), errors.Is(err, provider.ErrCardNotSupported):
+		code = codes.FailedPrecondition
+	case errors.Is(err, provider.ErrTimeout):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, provider.ErrDuplicate):
+		code = codes.AlreadyExists
+	}
+	return status.Errorf(code, "%s failed with: %s", op, err)
+}
+
+// hasRefund reports whether refund of id is recorded in charge
+func hasRefund(charge *paymentpb.Charge, refundId string) bool {
+	for _, refund := range charge.GetRefunds() {
+		if refund.GetId() == refundId {
+			return true
+		}
+	}
+	return false
+}
+
+// pendingRefund returns path of pending refund of id in charge document,
+// pending refunds are kept outside of paymentpb.Charge as refund id to
+// reservation, decoding a charge drops them
+func pendingRefund(refundId string) string {
+	return "pendingrefunds." + refundId
+}
+
+// reservation of a pending refund, amount reserved and time of reserve
+// in unix nanoseconds
+type reservation struct {
+	Amount   uint64
+	Reserved int64
+}
+
+// pending returns refunds reserved but not completed yet in document
+func pending(doc bson.M) (refunds map[string]reservation) {
+	if err := mapstructure.Decode(doc["pendingrefunds"], &refunds); err != nil {
+		log.Errorf("decode pending refunds error with: %s", err)
+	}
 	return
 }
 
+// refundLease returns RefundLease, or default lease if it is not set
+func (s Service) refundLease() time.Duration {
+	if s.RefundLease > 0 {
+		return s.RefundLease
+	}
+	return defaultRefundLease
+}
+
+// refundable rejects amount larger than what is left of charge
+func refundable(charge *paymentpb.Charge, amount uint64) error {
+	if left := charge.GetChargeAmount() - charge.GetRefundAmount(); amount == 0 || amount > left {
+		return status.Errorf(codes.FailedPrecondition, "charge %s: can not refund %d of %d left", charge.GetId(), amount, left)
+	}
+	return nil
+}
+
+// decode converts stored document into charge
+func decode(doc bson.M) (charge *paymentpb.Charge, err error) {
+	if err = mapstructure.Decode(doc, &charge); err != nil {
+		log.Error(err)
+		return nil, proxy.ToStatus(err)
+	}
+	return charge, nil
+}
+
 // Create Payment Service client
-func NewClient(config *cfg.ProxyConfig, cancel context.CancelFunc) (client *proxy.Client) {
-	client, _ = proxy.NewClient(config, ns, cancel)
+func NewClient(config *cfg.ProxyConfig, cancel context.CancelFunc) (client proxy.Storage, err error) {
+	if client, err = proxy.NewStorage(config, ns, cancel); err != nil {
+		return nil, fmt.Errorf("%s: create storage error with: %w", ns, err)
+	}
+	return
+}
+
+// Create Payment Service client on connections shared by manager
+func NewSharedClient(manager *proxy.Manager) (client proxy.Storage, err error) {
+	if client, err = manager.Storage(ns); err != nil {
+		return nil, fmt.Errorf("%s: create storage error with: %w", ns, err)
+	}
 	return
 }
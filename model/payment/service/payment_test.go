@@ -15,3 +15,372 @@
  */
 
 package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/xidongc-wish/mgo/bson"
+	"github.com/xidongc/mongo_ebenchmark/model/payment/paymentpb"
+	"github.com/xidongc/mongo_ebenchmark/model/payment/service/provider"
+	"github.com/xidongc/mongo_ebenchmark/mprpc"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"github.com/xidongc/mongo_ebenchmark/pkg/proxy"
+	"github.com/xidongc/mongo_ebenchmark/pkg/proxy/proxytest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRefundCharge(t *testing.T) {
+	server, ctx, cancel := proxytest.Start(t)
+
+	storage, err := NewClient(server.Config(), cancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := &Service{Storage: storage}
+	defer service.Storage.Close()
+
+	newCharge := func(amount uint64) *paymentpb.Charge {
+		charge, err := service.NewCharge(ctx, &paymentpb.ChargeRequest{
+			Currency:          paymentpb.Currency_USD,
+			Amount:            amount,
+			Card:              &paymentpb.Card{Type: paymentpb.CardType_Visa},
+			PaymentProviderId: paymentpb.PaymentProviderId_AliPay,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return charge
+	}
+
+	charge := newCharge(1000)
+	stored, err := service.Get(ctx, &paymentpb.GetRequest{Id: charge.GetId()})
+	if err != nil || stored.GetChargeAmount() != 1000 || stored.GetPaymentProviderId() != paymentpb.PaymentProviderId_AliPay {
+		t.Fatalf("unexpected stored charge %+v, %v", stored, err)
+	}
+
+	partial := &paymentpb.RefundRequest{Id: charge.GetId(), Amount: 300, RefundId: "r1", Reason: paymentpb.RefundReason_Duplicate}
+	for i := 0; i < 2; i++ {
+		refunded, err := service.RefundCharge(ctx, partial)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if refunded.GetRefundAmount() != 300 || refunded.GetRefunded() || len(refunded.GetRefunds()) != 1 {
+			t.Errorf("expect refunded once, got %+v", refunded)
+		}
+	}
+
+	_, err = service.RefundCharge(ctx, &paymentpb.RefundRequest{Id: charge.GetId(), Amount: 800, RefundId: "r2"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expect refund over charge rejected, got %v", err)
+	}
+	refunded, err := service.RefundCharge(ctx, &paymentpb.RefundRequest{Id: charge.GetId(), RefundId: "r3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refunded.GetRefundAmount() != 1000 || !refunded.GetRefunded() || refunded.GetRefunds()[1].GetRefundAmount() != 700 {
+		t.Errorf("expect remaining amount refunded, got %+v", refunded)
+	}
+	if refunded.GetRefunds()[0].GetReason() != paymentpb.RefundReason_Duplicate || refunded.GetRefunds()[0].GetCreated() == 0 {
+		t.Errorf("expect refund reason and time recorded, got %+v", refunded.GetRefunds()[0])
+	}
+	_, err = service.RefundCharge(ctx, &paymentpb.RefundRequest{Id: charge.GetId(), Amount: 1})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expect refund of refunded charge rejected, got %v", err)
+	}
+	_, err = service.RefundCharge(ctx, &paymentpb.RefundRequest{Id: "not exist", Amount: 1})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expect not found, got %v", err)
+	}
+
+	charge = newCharge(500)
+	var wg sync.WaitGroup
+	var succeeded int64
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := service.RefundCharge(ctx, &paymentpb.RefundRequest{Id: charge.GetId(), Amount: 100, RefundId: fmt.Sprint(i)})
+			if err == nil {
+				atomic.AddInt64(&succeeded, 1)
+			} else if status.Code(err) != codes.FailedPrecondition {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	stored, err = service.Get(ctx, &paymentpb.GetRequest{Id: charge.GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if succeeded != 5 || stored.GetRefundAmount() != 500 || len(stored.GetRefunds()) != 5 || !stored.GetRefunded() {
+		t.Errorf("expect 5 refunds without exceeding charge, got %d, %+v", succeeded, stored)
+	}
+}
+
+// countingProvider counts refunds reaching provider, refunds time out
+// while failRefund is set
+type countingProvider struct {
+	Provider
+	refunds    int64
+	failRefund int32
+}
+
+func (p *countingProvider) Refund(chargeId string, refundId string, amount uint64, currency paymentpb.Currency, reason paymentpb.RefundReason) (*paymentpb.Refund, error) {
+	atomic.AddInt64(&p.refunds, 1)
+	if atomic.LoadInt32(&p.failRefund) == 1 {
+		return nil, provider.ErrTimeout
+	}
+	return p.Provider.Refund(chargeId, refundId, amount, currency, reason)
+}
+
+// Test refunds are reserved before reaching provider
+func TestRefundReserve(t *testing.T) {
+	server, ctx, cancel := proxytest.Start(t)
+
+	mock, err := provider.NewMock(provider.MockOptions{Latency: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	counting := &countingProvider{Provider: mock}
+	registry, err := NewRegistry(&cfg.PaymentConfig{PaymentProviders: []string{"Mock"}}, counting)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage, err := NewClient(server.Config(), cancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := &Service{Storage: storage, Providers: registry}
+	defer service.Storage.Close()
+
+	newCharge := func(amount uint64) *paymentpb.Charge {
+		charge, err := service.NewCharge(ctx, &paymentpb.ChargeRequest{
+			Currency:          paymentpb.Currency_USD,
+			Amount:            amount,
+			Card:              &paymentpb.Card{Type: paymentpb.CardType_Visa},
+			PaymentProviderId: paymentpb.PaymentProviderId_Mock,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return charge
+	}
+	refundConcurrently := func(charge *paymentpb.Charge, refundId func(i int) string) (succeeded int64) {
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, err := service.RefundCharge(ctx, &paymentpb.RefundRequest{Id: charge.GetId(), Amount: 100, RefundId: refundId(i)})
+				if err == nil {
+					atomic.AddInt64(&succeeded, 1)
+				} else if code := status.Code(err); code != codes.FailedPrecondition && code != codes.Aborted {
+					t.Error(err)
+				}
+			}(i)
+		}
+		wg.Wait()
+		return
+	}
+
+	// different refund ids, provider is called once per reserved refund
+	charge := newCharge(500)
+	succeeded := refundConcurrently(charge, func(i int) string { return fmt.Sprint(i) })
+	stored, err := service.Get(ctx, &paymentpb.GetRequest{Id: charge.GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if succeeded != 5 || counting.refunds != 5 || stored.GetRefundAmount() != 500 || len(stored.GetRefunds()) != 5 {
+		t.Errorf("expect 5 refunds and provider calls, got %d, %d, %+v", succeeded, counting.refunds, stored)
+	}
+
+	// same refund id, provider is called once
+	counting.refunds = 0
+	charge = newCharge(500)
+	refundConcurrently(charge, func(int) string { return "same" })
+	stored, err = service.Get(ctx, &paymentpb.GetRequest{Id: charge.GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counting.refunds != 1 || stored.GetRefundAmount() != 100 || len(stored.GetRefunds()) != 1 {
+		t.Errorf("expect refund of same id refunded once, got %d, %+v", counting.refunds, stored)
+	}
+
+	// reservation is released once provider fails
+	charge = newCharge(100)
+	atomic.StoreInt32(&counting.failRefund, 1)
+	req := &paymentpb.RefundRequest{Id: charge.GetId(), RefundId: "retried"}
+	if _, err := service.RefundCharge(ctx, req); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("expect provider timeout, got %v", err)
+	}
+	stored, err = service.Get(ctx, &paymentpb.GetRequest{Id: charge.GetId()})
+	if err != nil || stored.GetRefundAmount() != 0 || stored.GetRefunded() {
+		t.Errorf("expect reservation released, got %+v, %v", stored, err)
+	}
+	atomic.StoreInt32(&counting.failRefund, 0)
+	refunded, err := service.RefundCharge(ctx, req)
+	if err != nil || refunded.GetRefundAmount() != 100 || !refunded.GetRefunded() || len(refunded.GetRefunds()) != 1 {
+		t.Errorf("expect retry refunded, got %+v, %v", refunded, err)
+	}
+}
+
+// racingStorage refunds raced of charge amount before the first
+// reservation of a refund, as if a concurrent refund won
+type racingStorage struct {
+	proxy.Storage
+	raced uint64
+	once  sync.Once
+}
+
+func (s *racingStorage) Update(ctx context.Context, param *proxy.UpdateParam) (*mprpc.ChangeInfo, error) {
+	if filter := param.Filter; filter["refundamount"] != nil {
+		s.once.Do(func() {
+			race := &proxy.UpdateParam{Filter: bson.M{"id": filter["id"]}, Update: bson.M{"$inc": bson.M{"refundamount": s.raced}}}
+			if _, err := s.Storage.Update(ctx, race); err != nil {
+				panic(err)
+			}
+		})
+	}
+	return s.Storage.Update(ctx, param)
+}
+
+// Test refund amount left is recomputed once a concurrent refund wins, and
+// pending refunds of lost requests are taken over once lease expired
+func TestRefundReconcile(t *testing.T) {
+	server, ctx, cancel := proxytest.Start(t)
+
+	mock, err := provider.NewMock(provider.MockOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	counting := &countingProvider{Provider: mock}
+	registry, err := NewRegistry(&cfg.PaymentConfig{PaymentProviders: []string{"Mock"}}, counting)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage, err := NewClient(server.Config(), cancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	racing := &racingStorage{Storage: storage, raced: 100}
+	service := &Service{Storage: racing, Providers: registry, RefundLease: time.Minute}
+	defer service.Storage.Close()
+
+	newCharge := func() *paymentpb.Charge {
+		charge, err := service.NewCharge(ctx, &paymentpb.ChargeRequest{
+			Currency:          paymentpb.Currency_USD,
+			Amount:            500,
+			Card:              &paymentpb.Card{Type: paymentpb.CardType_Visa},
+			PaymentProviderId: paymentpb.PaymentProviderId_Mock,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return charge
+	}
+	charge := newCharge()
+	refunded, err := service.RefundCharge(ctx, &paymentpb.RefundRequest{Id: charge.GetId(), RefundId: "full"})
+	if err != nil || refunded.GetRefundAmount() != 500 || refunded.GetRefunds()[0].GetRefundAmount() != 400 {
+		t.Fatalf("expect what is left refunded, got %+v, %v", refunded, err)
+	}
+
+	// pending refunds left by lost requests, one within lease
+	charge = newCharge()
+	lost := func(refundId string, reserved time.Time) {
+		param := &proxy.UpdateParam{
+			Filter: bson.M{"id": charge.GetId()},
+			Update: bson.M{
+				"$inc": bson.M{"refundamount": 50},
+				"$set": bson.M{pendingRefund(refundId): bson.M{"amount": uint64(50), "reserved": reserved.UnixNano()}},
+			},
+		}
+		if _, err := storage.Update(ctx, param); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lost("recent", time.Now())
+	lost("expired", time.Now().Add(-2*time.Minute))
+
+	counting.refunds = 0
+	if _, err := service.RefundCharge(ctx, &paymentpb.RefundRequest{Id: charge.GetId(), RefundId: "recent"}); status.Code(err) != codes.Aborted {
+		t.Errorf("expect refund within lease in progress, got %v", err)
+	}
+	refunded, err = service.RefundCharge(ctx, &paymentpb.RefundRequest{Id: charge.GetId(), RefundId: "expired", Amount: 10})
+	if err != nil || !hasRefund(refunded, "expired") || refunded.GetRefunds()[0].GetRefundAmount() != 50 || refunded.GetRefundAmount() != 100 {
+		t.Fatalf("expect expired refund completed with reserved amount, got %+v, %v", refunded, err)
+	}
+	doc, err := service.find(ctx, charge.GetId())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := pending(doc)["expired"]; ok || counting.refunds != 1 {
+		t.Errorf("expect expired refund no longer pending and refunded once, got %v, %d", pending(doc), counting.refunds)
+	}
+}
+
+func TestProviderRegistry(t *testing.T) {
+	server, ctx, cancel := proxytest.Start(t)
+
+	if _, err := NewRegistry(&cfg.PaymentConfig{PaymentProviders: []string{"Paypal"}}, &provider.AliPay{}); err == nil {
+		t.Error("expect error on provider not available")
+	}
+	if _, err := NewRegistry(&cfg.PaymentConfig{PaymentProviders: []string{"AliPay"}, PaymentFailover: map[string]string{"AliPay": "Mock"}}, &provider.AliPay{}); err == nil {
+		t.Error("expect error on failover to provider not enabled")
+	}
+
+	// mock always times out and fails over to alipay
+	mock, err := provider.NewMock(provider.MockOptions{TimeoutRate: 1, Cards: []string{"Visa", "Discover"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &cfg.PaymentConfig{
+		PaymentProviders: []string{"AliPay", "Mock"},
+		PaymentFailover:  map[string]string{"Mock": "AliPay"},
+	}
+	registry, err := NewRegistry(config, &provider.AliPay{}, mock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage, err := NewClient(server.Config(), cancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := &Service{Storage: storage, Providers: registry}
+	defer service.Storage.Close()
+
+	charge := func(providerId paymentpb.PaymentProviderId, cardType paymentpb.CardType) (*paymentpb.Charge, error) {
+		return service.NewCharge(ctx, &paymentpb.ChargeRequest{
+			Currency:          paymentpb.Currency_USD,
+			Amount:            100,
+			Card:              &paymentpb.Card{Type: cardType},
+			PaymentProviderId: providerId,
+		})
+	}
+
+	if _, err := charge(paymentpb.PaymentProviderId_PROVIDER_Reserved, paymentpb.CardType_Visa); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expect unknown provider rejected, got %v", err)
+	}
+	if _, err := charge(paymentpb.PaymentProviderId_WeChat, paymentpb.CardType_Visa); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expect provider not enabled rejected, got %v", err)
+	}
+	if _, err := charge(paymentpb.PaymentProviderId_Mock, paymentpb.CardType_Mastercard); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expect card not supported rejected, got %v", err)
+	}
+
+	charged, err := charge(paymentpb.PaymentProviderId_Mock, paymentpb.CardType_Visa)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if charged.GetPaymentProviderId() != paymentpb.PaymentProviderId_AliPay {
+		t.Errorf("expect charge failed over to alipay, got %s", charged.GetPaymentProviderId())
+	}
+	// alipay does not support discover, no failover
+	if _, err := charge(paymentpb.PaymentProviderId_Mock, paymentpb.CardType_Discover); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("expect mock timeout, got %v", err)
+	}
+}
@@ -21,6 +21,6 @@ import "github.com/xidongc/mongo_ebenchmark/model/payment/paymentpb"
 type Provider interface {
 	ProviderId() paymentpb.PaymentProviderId
 	Charge(req *paymentpb.ChargeRequest) (*paymentpb.Charge, error)
-	Refund(chargeId string, amount uint64, currency paymentpb.Currency, reason paymentpb.RefundReason) (*paymentpb.Refund, error)
+	Refund(chargeId string, refundId string, amount uint64, currency paymentpb.Currency, reason paymentpb.RefundReason) (*paymentpb.Refund, error)
 	SupportedCards() []paymentpb.CardType
 }
@@ -30,7 +30,7 @@ type AliPay struct {
 }
 
 func (*AliPay) ProviderId() (paymentId paymentpb.PaymentProviderId) {
-	return
+	return paymentpb.PaymentProviderId_AliPay
 }
 
 func (a *AliPay) Charge(req *paymentpb.ChargeRequest) (charge *paymentpb.Charge, err error) {
@@ -55,10 +55,30 @@ func (a *AliPay) Charge(req *paymentpb.ChargeRequest) (charge *paymentpb.Charge,
 	return
 }
 
-func (*AliPay) Refund(chargeId string, amount uint64, currency paymentpb.Currency, reason paymentpb.RefundReason) (refund *paymentpb.Refund, err error) {
+// Refund refunds amount of charge, refund id is sent as out request no,
+// so alipay refunds retries of the same refund id once
+func (*AliPay) Refund(chargeId string, refundId string, amount uint64, currency paymentpb.Currency, reason paymentpb.RefundReason) (refund *paymentpb.Refund, err error) {
+	trade := alipay.TradeRefund{}
+	trade.OutTradeNo = chargeId
+	trade.OutRequestNo = refundId
+	trade.RefundAmount = strconv.Itoa(int(amount))
+	trade.RefundReason = reason.String()
+
+	refund = &paymentpb.Refund{
+		Id:               refundId,
+		RefundAmount:     amount,
+		ProviderRefundId: fmt.Sprintf("%s", uuid.New()),
+		Reason:           reason,
+		Created:          time.Now().UnixNano(),
+	}
 	return
 }
 
 func (*AliPay) SupportedCards() (cardType []paymentpb.CardType) {
-	return
+	return []paymentpb.CardType{
+		paymentpb.CardType_Mastercard,
+		paymentpb.CardType_Visa,
+		paymentpb.CardType_AmericanExpress,
+		paymentpb.CardType_JCB,
+	}
 }
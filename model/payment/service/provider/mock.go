@@ -0,0 +1,213 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/xidongc/mongo_ebenchmark/model/payment/paymentpb"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Latency distributions of mock provider
+const (
+	Fixed       = "fixed"
+	Uniform     = "uniform"
+	Normal      = "normal"
+	Exponential = "exponential"
+)
+
+// Errors returned by mock provider
+var (
+	ErrDeclined         = errors.New("card declined")
+	ErrTimeout          = errors.New("provider timeout")
+	ErrDuplicate        = errors.New("duplicate charge")
+	ErrCardNotSupported = errors.New("card type not supported")
+)
+
+// MockOptions of mock provider, rates are ratios between 0 and 1
+type MockOptions struct {
+	Seed          int64         `long:"mock-seed" default:"1" description:"seed of mock provider, same seed gives same latency and failures"`
+	Latency       time.Duration `long:"mock-latency" description:"mean latency of mock provider"`
+	Jitter        time.Duration `long:"mock-jitter" description:"latency stddev of normal, or half range of uniform distribution"`
+	Distribution  string        `long:"mock-latency-dist" default:"fixed" choice:"fixed" choice:"uniform" choice:"normal" choice:"exponential" description:"latency distribution of mock provider"`
+	DeclineRate   float64       `long:"mock-decline-rate" description:"ratio of charges declined"`
+	TimeoutRate   float64       `long:"mock-timeout-rate" description:"ratio of calls timed out"`
+	Timeout       time.Duration `long:"mock-timeout" default:"5s" description:"time a timed out call takes"`
+	DuplicateRate float64       `long:"mock-duplicate-rate" description:"ratio of charges rejected as duplicate"`
+	Cards         []string      `long:"mock-card" description:"card type supported, eg: Visa, all types if not specified"`
+}
+
+// Validate checks rates and card types
+func (o *MockOptions) Validate() error {
+	for name, rate := range map[string]float64{"decline": o.DeclineRate, "timeout": o.TimeoutRate, "duplicate": o.DuplicateRate} {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("mock %s rate %v is not between 0 and 1", name, rate)
+		}
+	}
+	if o.DeclineRate+o.TimeoutRate+o.DuplicateRate > 1 {
+		return errors.New("sum of mock failure rates is larger than 1")
+	}
+	if o.Latency < 0 || o.Jitter < 0 || o.Timeout < 0 {
+		return errors.New("mock latency, jitter and timeout should not be negative")
+	}
+	switch o.Distribution {
+	case "", Fixed, Uniform, Normal, Exponential:
+	default:
+		return fmt.Errorf("unknown mock latency distribution %s", o.Distribution)
+	}
+	for _, card := range o.Cards {
+		if _, ok := paymentpb.CardType_value[card]; !ok {
+			return fmt.Errorf("unknown card type %s", card)
+		}
+	}
+	return nil
+}
+
+// Mock is an offline payment provider with configurable latency and
+// failures, they are drawn from a rng seeded by Seed, so a sequence of
+// calls gets the same latency and failures in every run
+type Mock struct {
+	options MockOptions
+	cards   []paymentpb.CardType
+	lock    sync.Mutex
+	rng     *rand.Rand
+}
+
+// Create mock provider
+func NewMock(options MockOptions) (*Mock, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	mock := &Mock{
+		options: options,
+		rng:     rand.New(rand.NewSource(options.Seed)),
+	}
+	for _, card := range options.Cards {
+		mock.cards = append(mock.cards, paymentpb.CardType(paymentpb.CardType_value[card]))
+	}
+	return mock, nil
+}
+
+func (*Mock) ProviderId() paymentpb.PaymentProviderId {
+	return paymentpb.PaymentProviderId_Mock
+}
+
+// Charge waits for drawn latency, then fails with ErrTimeout, ErrDeclined
+// or ErrDuplicate by their rates, card types not supported are declined
+// with ErrCardNotSupported
+func (m *Mock) Charge(req *paymentpb.ChargeRequest) (charge *paymentpb.Charge, err error) {
+	if req == nil {
+		return
+	}
+	latency, roll := m.draw()
+	if roll < m.options.TimeoutRate {
+		time.Sleep(m.options.Timeout)
+		return nil, ErrTimeout
+	}
+	time.Sleep(latency)
+
+	roll -= m.options.TimeoutRate
+	switch {
+	case !m.supports(req.GetCard().GetType()):
+		return nil, ErrCardNotSupported
+	case roll < m.options.DeclineRate:
+		return nil, ErrDeclined
+	case roll < m.options.DeclineRate+m.options.DuplicateRate:
+		return nil, ErrDuplicate
+	}
+
+	charge = &paymentpb.Charge{
+		Id:           fmt.Sprintf("mock-%s", uuid.New()),
+		Currency:     req.Currency,
+		Paid:         true,
+		ChargeAmount: req.Amount,
+		UserId:       req.GetUserId(),
+	}
+	charge.Created = time.Now().UnixNano()
+	charge.Updated = charge.Created
+	return
+}
+
+// Refund waits for drawn latency, then fails with ErrTimeout by its rate
+func (m *Mock) Refund(chargeId string, refundId string, amount uint64, currency paymentpb.Currency, reason paymentpb.RefundReason) (refund *paymentpb.Refund, err error) {
+	latency, roll := m.draw()
+	if roll < m.options.TimeoutRate {
+		time.Sleep(m.options.Timeout)
+		return nil, ErrTimeout
+	}
+	time.Sleep(latency)
+
+	refund = &paymentpb.Refund{
+		Id:               refundId,
+		RefundAmount:     amount,
+		ProviderRefundId: fmt.Sprintf("mock-%s", uuid.New()),
+		Reason:           reason,
+		Created:          time.Now().UnixNano(),
+	}
+	return
+}
+
+// SupportedCards returns card types configured, all types if none
+func (m *Mock) SupportedCards() (cardType []paymentpb.CardType) {
+	if len(m.cards) > 0 {
+		return m.cards
+	}
+	for value := range paymentpb.CardType_name {
+		cardType = append(cardType, paymentpb.CardType(value))
+	}
+	return
+}
+
+// supports reports whether card type is supported
+func (m *Mock) supports(cardType paymentpb.CardType) bool {
+	if len(m.cards) == 0 {
+		return true
+	}
+	for _, card := range m.cards {
+		if card == cardType {
+			return true
+		}
+	}
+	return false
+}
+
+// draw returns latency of a call and a roll in [0, 1) deciding failure
+func (m *Mock) draw() (latency time.Duration, roll float64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	mean := float64(m.options.Latency)
+	jitter := float64(m.options.Jitter)
+	switch m.options.Distribution {
+	case Uniform:
+		latency = time.Duration(mean - jitter + m.rng.Float64()*2*jitter)
+	case Normal:
+		latency = time.Duration(mean + m.rng.NormFloat64()*jitter)
+	case Exponential:
+		latency = time.Duration(m.rng.ExpFloat64() * mean)
+	default:
+		latency = m.options.Latency
+	}
+	if latency < 0 {
+		latency = 0
+	}
+	return latency, m.rng.Float64()
+}
@@ -0,0 +1,98 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package provider
+
+import (
+	"errors"
+	"github.com/xidongc/mongo_ebenchmark/model/payment/paymentpb"
+	"math"
+	"testing"
+	"time"
+)
+
+// outcomes charges n times and returns error of each charge
+func outcomes(t *testing.T, options MockOptions, n int) (errs []error) {
+	mock, err := NewMock(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		_, err := mock.Charge(&paymentpb.ChargeRequest{Amount: 100, Card: &paymentpb.Card{Type: paymentpb.CardType_Visa}})
+		errs = append(errs, err)
+	}
+	return
+}
+
+// Test on failure rates and determinism of mock provider
+func TestMockCharge(t *testing.T) {
+	options := MockOptions{Seed: 7, DeclineRate: 0.2, TimeoutRate: 0.1, DuplicateRate: 0.05}
+	first := outcomes(t, options, 2000)
+	second := outcomes(t, options, 2000)
+
+	counts := make(map[error]int)
+	for i, err := range first {
+		if err != second[i] {
+			t.Fatalf("charge %d differs with same seed: %v and %v", i, err, second[i])
+		}
+		counts[err]++
+	}
+	for err, rate := range map[error]float64{ErrDeclined: 0.2, ErrTimeout: 0.1, ErrDuplicate: 0.05, nil: 0.65} {
+		if got := float64(counts[err]) / 2000; math.Abs(got-rate) > 0.03 {
+			t.Errorf("expect rate of %v around %v, got %v", err, rate, got)
+		}
+	}
+
+	options.Seed = 8
+	third := outcomes(t, options, 2000)
+	same := true
+	for i := range first {
+		same = same && first[i] == third[i]
+	}
+	if same {
+		t.Error("expect different outcomes with different seed")
+	}
+}
+
+// Test on card support and latency of mock provider
+func TestMockCards(t *testing.T) {
+	mock, err := NewMock(MockOptions{Cards: []string{"Mastercard"}, Latency: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cards := mock.SupportedCards(); len(cards) != 1 || cards[0] != paymentpb.CardType_Mastercard {
+		t.Errorf("expect mastercard only, got %v", cards)
+	}
+
+	begin := time.Now()
+	charge, err := mock.Charge(&paymentpb.ChargeRequest{Amount: 100, Card: &paymentpb.Card{Type: paymentpb.CardType_Mastercard}})
+	if err != nil || charge.GetChargeAmount() != 100 || !charge.GetPaid() {
+		t.Errorf("unexpected charge %+v, %v", charge, err)
+	}
+	if time.Since(begin) < 5*time.Millisecond {
+		t.Error("expect charge to take mock latency")
+	}
+	if _, err := mock.Charge(&paymentpb.ChargeRequest{Amount: 100, Card: &paymentpb.Card{Type: paymentpb.CardType_Visa}}); !errors.Is(err, ErrCardNotSupported) {
+		t.Errorf("expect card not supported, got %v", err)
+	}
+
+	if _, err := NewMock(MockOptions{Cards: []string{"Bitcoin"}}); err == nil {
+		t.Error("expect error on unknown card type")
+	}
+	if _, err := NewMock(MockOptions{DeclineRate: 0.8, TimeoutRate: 0.5}); err == nil {
+		t.Error("expect error on failure rates larger than 1")
+	}
+}
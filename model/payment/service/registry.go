@@ -0,0 +1,131 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package service
+
+import (
+	"fmt"
+	"github.com/xidongc/mongo_ebenchmark/model/payment/paymentpb"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Registry of payment providers keyed by provider id, a provider may fail
+// over to a secondary provider on errors
+type Registry struct {
+	providers map[paymentpb.PaymentProviderId]Provider
+	failover  map[paymentpb.PaymentProviderId]paymentpb.PaymentProviderId
+}
+
+// Create Registry with providers enabled in cfg, providers are the
+// implementations available, each registered by its ProviderId
+func NewRegistry(config *cfg.PaymentConfig, providers ...Provider) (*Registry, error) {
+	available := make(map[paymentpb.PaymentProviderId]Provider)
+	for _, p := range providers {
+		available[p.ProviderId()] = p
+	}
+
+	registry := &Registry{
+		providers: make(map[paymentpb.PaymentProviderId]Provider),
+		failover:  make(map[paymentpb.PaymentProviderId]paymentpb.PaymentProviderId),
+	}
+	for _, name := range config.PaymentProviders {
+		id, err := providerId(name)
+		if err != nil {
+			return nil, err
+		}
+		p, ok := available[id]
+		if !ok {
+			return nil, fmt.Errorf("payment provider %s is not available", name)
+		}
+		registry.Register(p)
+	}
+	for primary, secondary := range config.PaymentFailover {
+		primaryId, err := providerId(primary)
+		if err != nil {
+			return nil, err
+		}
+		secondaryId, err := providerId(secondary)
+		if err != nil {
+			return nil, err
+		}
+		if err = registry.Failover(primaryId, secondaryId); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// Register provider by its ProviderId, a registered provider of the same
+// id is replaced
+func (r *Registry) Register(p Provider) {
+	r.providers[p.ProviderId()] = p
+}
+
+// Failover sets secondary provider primary fails over to, both should be
+// registered
+func (r *Registry) Failover(primary paymentpb.PaymentProviderId, secondary paymentpb.PaymentProviderId) error {
+	if primary == secondary {
+		return fmt.Errorf("payment provider %s can not fail over to itself", primary)
+	}
+	for _, id := range []paymentpb.PaymentProviderId{primary, secondary} {
+		if _, ok := r.providers[id]; !ok {
+			return fmt.Errorf("payment provider %s is not enabled", id)
+		}
+	}
+	r.failover[primary] = secondary
+	return nil
+}
+
+// Provider returns provider of id, unknown providers are rejected with
+// InvalidArgument
+func (r *Registry) Provider(id paymentpb.PaymentProviderId) (Provider, error) {
+	p, ok := r.providers[id]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown payment provider %s", id)
+	}
+	return p, nil
+}
+
+// Secondary returns provider id fails over to, if any
+func (r *Registry) Secondary(id paymentpb.PaymentProviderId) (Provider, bool) {
+	secondary, ok := r.failover[id]
+	if !ok {
+		return nil, false
+	}
+	p, ok := r.providers[secondary]
+	return p, ok
+}
+
+// providerId parses provider name, eg: AliPay
+func providerId(name string) (paymentpb.PaymentProviderId, error) {
+	id, ok := paymentpb.PaymentProviderId_value[name]
+	if !ok || id == int32(paymentpb.PaymentProviderId_PROVIDER_Reserved) {
+		return 0, fmt.Errorf("unknown payment provider %s", name)
+	}
+	return paymentpb.PaymentProviderId(id), nil
+}
+
+// supportsCard reports whether card type is one of provider supported cards
+func supportsCard(p Provider, cardType paymentpb.CardType) bool {
+	for _, card := range p.SupportedCards() {
+		if card == cardType {
+			return true
+		}
+	}
+	return false
+}
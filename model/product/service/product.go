@@ -18,7 +18,7 @@ package service
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"github.com/mitchellh/mapstructure"
 	log "github.com/sirupsen/logrus"
 	"github.com/xidongc-wish/mgo/bson"
@@ -27,13 +27,16 @@ import (
 	"github.com/xidongc/mongo_ebenchmark/model/sku/skupb"
 	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
 	"github.com/xidongc/mongo_ebenchmark/pkg/proxy"
+	"github.com/xidongc/mongo_ebenchmark/pkg/tracing"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"strings"
 )
 
 const ns = "product"
 
 type Service struct {
-	Storage    proxy.Client
+	Storage    proxy.Storage
 	Amplifier  cfg.Amplifier
 	SkuService *skuService.Service
 }
@@ -41,7 +44,9 @@ type Service struct {
 // Create Product
 func (s Service) New(ctx context.Context, req *productpb.NewRequest) (*productpb.Product, error) {
 	if _, err := s.Get(ctx, &productpb.GetRequest{Id: req.Id}); err == nil {
-		return nil, errors.New("please use update for exist record")
+		return nil, status.Error(codes.AlreadyExists, "please use update for exist record")
+	} else if status.Code(err) != codes.NotFound {
+		return nil, err
 	}
 
 	product := productpb.Product{
@@ -66,7 +71,7 @@ func (s Service) New(ctx context.Context, req *productpb.NewRequest) (*productpb
 
 	if err := s.Storage.Insert(ctx, param); err != nil {
 		log.Error(err)
-		return nil, err
+		return nil, proxy.ToStatus(err)
 	}
 	log.Infof("%+v", product)
 	return &product, nil
@@ -83,22 +88,28 @@ func (s Service) Get(ctx context.Context, req *productpb.GetRequest) (product *p
 
 	results, err := s.Storage.Find(ctx, param)
 
-	if err != nil || len(results) > 1 {
+	if err != nil {
 		log.Error(err)
-		return
+		return nil, proxy.ToStatus(err)
+	} else if len(results) > 1 {
+		return nil, status.Errorf(codes.FailedPrecondition, "duplicate product %s", req.GetId())
 	} else if len(results) == 0 {
-		return product, errors.New("no result found")
+		return nil, proxy.ToStatus(proxy.ErrNotFound)
 	}
 
 	err = mapstructure.Decode(results[0], &product)
 	if err != nil || product == nil {
-		log.Fatal(err)
+		log.Error(err)
+		return nil, status.Errorf(codes.Internal, "decode product %s error", req.GetId())
 	}
 
-	skus, err := s.SkuService.GetProductSkus(ctx, &skupb.GetProductSkusRequest{ProductId: req.Id})
-	if skus != nil {
-		product.Skus = skus.GetSkus()
+	skuCtx, span := tracing.Start(ctx, "sku.Service.GetProductSkus", tracing.Internal)
+	skus, err := s.SkuService.GetProductSkus(skuCtx, &skupb.GetProductSkusRequest{ProductId: req.Id})
+	tracing.End(span, err)
+	if err != nil {
+		return nil, err
 	}
+	product.Skus = skus.GetSkus()
 	return product, nil
 }
 
@@ -107,7 +118,7 @@ func (s Service) Update(ctx context.Context, req *productpb.UpdateRequest) (prod
 	var updateParams bson.M
 	if err = mapstructure.Decode(req, &updateParams); err != nil {
 		log.Error(err)
-		return
+		return nil, proxy.ToStatus(err)
 	}
 	updateLowerParams := make(bson.M, len(updateParams))
 	for key, val := range updateParams {
@@ -124,7 +135,8 @@ func (s Service) Update(ctx context.Context, req *productpb.UpdateRequest) (prod
 	}
 	changeInfo, err := s.Storage.Update(ctx, updateQuery)
 	if err != nil {
-		return
+		log.Error(err)
+		return nil, proxy.ToStatus(err)
 	}
 	log.Info(changeInfo)
 	product, err = s.Get(ctx, &productpb.GetRequest{Id: req.Id})
@@ -143,22 +155,40 @@ func (s Service) Delete(ctx context.Context, req *productpb.DeleteRequest) (*pro
 	_, err := s.Storage.Remove(ctx, removeQuery)
 	if err != nil {
 		log.Error(err)
-		return nil, err
+		return nil, proxy.ToStatus(err)
 	}
 
-	skus, err := s.SkuService.GetProductSkus(ctx, &skupb.GetProductSkusRequest{ProductId: req.Id})
-	if skus == nil {
-		log.Fatal(err)
+	skuCtx, span := tracing.Start(ctx, "sku.Service.GetProductSkus", tracing.Internal)
+	skus, err := s.SkuService.GetProductSkus(skuCtx, &skupb.GetProductSkusRequest{ProductId: req.Id})
+	tracing.End(span, err)
+	if err != nil {
+		log.Error(err)
 		return nil, err
 	}
 	for _, sku := range skus.GetSkus() {
-		_, err = s.SkuService.Delete(ctx, &skupb.DeleteRequest{Name: sku.GetName()})
+		skuCtx, span := tracing.Start(ctx, "sku.Service.Delete", tracing.Internal)
+		_, err = s.SkuService.Delete(skuCtx, &skupb.DeleteRequest{Name: sku.GetName()})
+		tracing.End(span, err)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
 	}
 	return &productpb.Empty{}, nil
 }
 
 // Create Product Service client
-func NewClient(config *cfg.ProxyConfig, cancel context.CancelFunc) (client *proxy.Client) {
-	client, _ = proxy.NewClient(config, ns, cancel)
+func NewClient(config *cfg.ProxyConfig, cancel context.CancelFunc) (client proxy.Storage, err error) {
+	if client, err = proxy.NewStorage(config, ns, cancel); err != nil {
+		return nil, fmt.Errorf("%s: create storage error with: %w", ns, err)
+	}
+	return
+}
+
+// Create Product Service client on connections shared by manager
+func NewSharedClient(manager *proxy.Manager) (client proxy.Storage, err error) {
+	if client, err = manager.Storage(ns); err != nil {
+		return nil, fmt.Errorf("%s: create storage error with: %w", ns, err)
+	}
 	return
 }
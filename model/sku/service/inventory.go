@@ -0,0 +1,119 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package sku
+
+import (
+	"context"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/xidongc-wish/mgo/bson"
+	"github.com/xidongc/mongo_ebenchmark/model/sku/skupb"
+	"github.com/xidongc/mongo_ebenchmark/pkg/proxy"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Reserve takes quantity of sku from the first warehouse with enough
+// stock, stock is decremented by conditional update on quantity, so
+// concurrent reservations never oversell, inventory is re-read when a
+// concurrent reservation wins. unlimited inventory is not decremented,
+// ResourceExhausted is returned if sku is out of stock
+func (s *Service) Reserve(ctx context.Context, name string, quantity int64) (warehouseId int64, err error) {
+	if quantity <= 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid quantity %d of sku %s", quantity, name)
+	}
+	for contended := true; contended; {
+		if err := ctx.Err(); err != nil {
+			return 0, proxy.ToStatus(err)
+		}
+		sku, err := s.Get(ctx, &skupb.GetRequest{Name: name})
+		if err != nil {
+			return 0, err
+		}
+		contended = false
+		for i, inventory := range sku.GetInventory() {
+			if unlimited(inventory) {
+				return inventory.GetWarehouseId(), nil
+			}
+			if inventory.GetQuantity() < quantity {
+				continue
+			}
+			param := &proxy.UpdateParam{
+				Filter: bson.M{
+					"Name": name,
+					fmt.Sprintf("Inventory.%d.warehouseid", i): inventory.GetWarehouseId(),
+					fmt.Sprintf("Inventory.%d.quantity", i):    bson.M{"$gte": quantity},
+				},
+				Update: bson.M{
+					"$inc": bson.M{fmt.Sprintf("Inventory.%d.quantity", i): -quantity},
+					"$set": bson.M{fmt.Sprintf("Inventory.%d.type", i): skupb.Inventory_Finite},
+				},
+				Amp: s.Amplifier,
+			}
+			changeInfo, err := s.Storage.Update(ctx, param)
+			if err != nil {
+				log.Error(err)
+				return 0, proxy.ToStatus(err)
+			}
+			if changeInfo.GetMatched() > 0 {
+				return inventory.GetWarehouseId(), nil
+			}
+			contended = true
+		}
+	}
+	return 0, status.Errorf(codes.ResourceExhausted, "sku %s is out of stock", name)
+}
+
+// Release puts quantity of sku reserved from warehouse back
+func (s *Service) Release(ctx context.Context, name string, warehouseId int64, quantity int64) (err error) {
+	sku, err := s.Get(ctx, &skupb.GetRequest{Name: name})
+	if err != nil {
+		return err
+	}
+	for i, inventory := range sku.GetInventory() {
+		if inventory.GetWarehouseId() != warehouseId {
+			continue
+		}
+		if unlimited(inventory) {
+			return nil
+		}
+		param := &proxy.UpdateParam{
+			Filter: bson.M{
+				"Name": name,
+				fmt.Sprintf("Inventory.%d.warehouseid", i): warehouseId,
+			},
+			Update: bson.M{"$inc": bson.M{fmt.Sprintf("Inventory.%d.quantity", i): quantity}},
+			Amp:    s.Amplifier,
+		}
+		changeInfo, err := s.Storage.Update(ctx, param)
+		if err != nil {
+			log.Error(err)
+			return proxy.ToStatus(err)
+		}
+		if changeInfo.GetMatched() > 0 {
+			return nil
+		}
+	}
+	return status.Errorf(codes.NotFound, "sku %s has no warehouse %d", name, warehouseId)
+}
+
+// unlimited reports whether inventory is not bounded by quantity. Infinite
+// is the zero type, so inventory without type reads as Infinite, it is
+// bounded if it holds quantity, and typed Finite once stock is taken
+func unlimited(inventory *skupb.Inventory) bool {
+	return inventory.GetType() == skupb.Inventory_Infinite && inventory.GetQuantity() == 0
+}
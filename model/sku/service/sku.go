@@ -18,20 +18,22 @@ package sku
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"github.com/mitchellh/mapstructure"
 	log "github.com/sirupsen/logrus"
 	"github.com/xidongc-wish/mgo/bson"
 	"github.com/xidongc/mongo_ebenchmark/model/sku/skupb"
 	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
 	"github.com/xidongc/mongo_ebenchmark/pkg/proxy"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const ns = "sku"
 
 // SKU Service
 type Service struct {
-	Storage   proxy.Client
+	Storage   proxy.Storage
 	Amplifier cfg.Amplifier
 }
 
@@ -47,16 +49,19 @@ func (s *Service) Get(ctx context.Context, req *skupb.GetRequest) (*skupb.Sku, e
 
 	results, err := s.Storage.Find(ctx, param)
 
-	if err != nil || len(results) > 1 {
+	if err != nil {
 		log.Error(err)
-		return &sku, err
+		return nil, proxy.ToStatus(err)
+	} else if len(results) > 1 {
+		return nil, status.Errorf(codes.FailedPrecondition, "duplicate sku %s", req.GetName())
 	} else if len(results) == 0 {
-		return &sku, errors.New("no result found")
+		return nil, proxy.ToStatus(proxy.ErrNotFound)
 	}
 
 	err = mapstructure.Decode(results[0], &sku)
 	if err != nil {
-		log.Fatal(err)
+		log.Error(err)
+		return nil, proxy.ToStatus(err)
 	}
 	log.Infof("received sku: %+v", sku)
 	return &sku, nil
@@ -71,7 +76,7 @@ func (s *Service) Delete(ctx context.Context, req *skupb.DeleteRequest) (*skupb.
 	changeInfo, err := s.Storage.Remove(ctx, removeQuery)
 	if err != nil {
 		log.Error(err)
-		return nil, err
+		return nil, proxy.ToStatus(err)
 	}
 	log.Info(changeInfo)
 	return &skupb.Empty{}, nil
@@ -82,6 +87,11 @@ func (s *Service) New(ctx context.Context, req *skupb.UpsertRequest) (sku *skupb
 
 	var inventories []*skupb.Inventory
 
+	// inventory without type holding quantity is bounded by it
+	if inventory := req.GetInventory(); inventory.GetType() == skupb.Inventory_Infinite && inventory.GetQuantity() > 0 {
+		inventory.Type = skupb.Inventory_Finite
+	}
+
 	query := proxy.QueryParam{
 		Filter:  bson.M{"Name": req.Name},
 		FindOne: true,
@@ -90,9 +100,11 @@ func (s *Service) New(ctx context.Context, req *skupb.UpsertRequest) (sku *skupb
 
 	result, err := s.Storage.Find(ctx, &query)
 
-	if err != nil || len(result) > 1 {
-		log.Fatal(err)
-		return
+	if err != nil {
+		log.Error(err)
+		return nil, proxy.ToStatus(err)
+	} else if len(result) > 1 {
+		return nil, status.Errorf(codes.FailedPrecondition, "duplicate sku %s", req.GetName())
 	} else if len(result) == 0 {
 		inventories = append(inventories, req.GetInventory())
 	} else if _, ok := result[0]["Inventory"]; ok && len(result) == 1 {
@@ -125,7 +137,7 @@ func (s *Service) New(ctx context.Context, req *skupb.UpsertRequest) (sku *skupb
 
 	if err = mapstructure.Decode(sku, &updateQuery); err != nil {
 		log.Error(err)
-		return
+		return nil, proxy.ToStatus(err)
 	}
 
 	param := &proxy.UpdateParam{
@@ -138,15 +150,12 @@ func (s *Service) New(ctx context.Context, req *skupb.UpsertRequest) (sku *skupb
 
 	changeInfo, err := s.Storage.Update(ctx, param)
 	if err != nil {
-		log.Error("error")
+		log.Errorf("sku error: storage failed with %s", err)
+		return nil, proxy.ToStatus(err)
 	}
 
 	log.Info(changeInfo)
 
-	if err != nil {
-		log.Errorf("sku error: storage failed with %s", err)
-	}
-
 	return
 }
 
@@ -161,10 +170,10 @@ func (s *Service) GetProductSkus(ctx context.Context, req *skupb.GetProductSkusR
 	results, err := s.Storage.Find(ctx, param)
 	if err != nil {
 		log.Error(err)
-		return
+		return nil, proxy.ToStatus(err)
 	}
 	if len(results) == 0 {
-		return
+		return &skupb.Skus{}, nil
 	}
 
 	var productSkus []*skupb.Sku
@@ -172,7 +181,8 @@ func (s *Service) GetProductSkus(ctx context.Context, req *skupb.GetProductSkusR
 		var sku *skupb.Sku
 		err = mapstructure.Decode(result, &sku)
 		if err != nil {
-			log.Fatal(err)
+			log.Error(err)
+			return nil, proxy.ToStatus(err)
 		}
 		log.Infof("received sku: %+v", sku)
 		productSkus = append(productSkus, sku)
@@ -184,7 +194,17 @@ func (s *Service) GetProductSkus(ctx context.Context, req *skupb.GetProductSkusR
 }
 
 // Create SKU Service client
-func NewClient(config *cfg.ProxyConfig, cancel context.CancelFunc) (client *proxy.Client) {
-	client, _ = proxy.NewClient(config, ns, cancel)
+func NewClient(config *cfg.ProxyConfig, cancel context.CancelFunc) (client proxy.Storage, err error) {
+	if client, err = proxy.NewStorage(config, ns, cancel); err != nil {
+		return nil, fmt.Errorf("%s: create storage error with: %w", ns, err)
+	}
+	return
+}
+
+// Create SKU Service client on connections shared by manager
+func NewSharedClient(manager *proxy.Manager) (client proxy.Storage, err error) {
+	if client, err = manager.Storage(ns); err != nil {
+		return nil, fmt.Errorf("%s: create storage error with: %w", ns, err)
+	}
 	return
 }
@@ -17,28 +17,24 @@
 package sku
 
 import (
-	"context"
-	"github.com/mitchellh/mapstructure"
-	"github.com/xidongc-wish/mgo/bson"
 	"github.com/xidongc/mongo_ebenchmark/model/sku/skupb"
 	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
-	"github.com/xidongc/mongo_ebenchmark/pkg/proxy"
+	"github.com/xidongc/mongo_ebenchmark/pkg/proxy/proxytest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sync"
+	"sync/atomic"
 	"testing"
 )
 
 func TestSkuServiceApi(t *testing.T) {
-	var result skupb.Sku
+	server, ctx, cancel := proxytest.Start(t)
 
-	param := &proxy.QueryParam{
-		Filter:  bson.M{"Name": "xidong"},
-		FindOne: true,
-		Amp:     cfg.MicroAmplifier(),
+	storage, err := NewClient(server.Config(), cancel)
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-
-	storage := NewClient(cfg.DefaultConfig(), cancel)
-
 	defer func() {
 		err := storage.Close()
 		if err != nil {
@@ -46,15 +42,148 @@ func TestSkuServiceApi(t *testing.T) {
 		}
 	}()
 
-	results, err := storage.Find(ctx, param)
-	if err != nil {
-		panic(err)
+	service := &Service{
+		Storage:   storage,
+		Amplifier: cfg.MicroAmplifier(),
+	}
+
+	req := &skupb.UpsertRequest{
+		Name:      "xidong",
+		ProductId: "1234567",
+		Price:     123,
+		Active:    true,
+		Inventory: &skupb.Inventory{SkuId: 123, WarehouseId: 12345},
+	}
+	if _, err := service.New(ctx, req); err != nil {
+		t.Fatal(err)
 	}
-	t.Log("start output... ")
-	t.Log(results[0])
-	err = mapstructure.Decode(results[0], &result)
+
+	result, err := service.Get(ctx, &skupb.GetRequest{Name: "xidong"})
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
+	}
+	if result.GetProductId() != req.GetProductId() {
+		t.Errorf("expect product id %s, got %s", req.GetProductId(), result.GetProductId())
 	}
 	t.Logf("%+v", result)
+
+	skus, err := service.GetProductSkus(ctx, &skupb.GetProductSkusRequest{ProductId: req.GetProductId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skus.GetSkus()) != 1 {
+		t.Errorf("expect 1 sku, got %d", len(skus.GetSkus()))
+	}
+
+	if _, err := service.Get(ctx, &skupb.GetRequest{Name: "not exist"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expect not found, got %v", err)
+	}
+}
+
+func TestSkuReserve(t *testing.T) {
+	server, ctx, cancel := proxytest.Start(t)
+
+	storage, err := NewClient(server.Config(), cancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := &Service{
+		Storage: storage,
+	}
+	defer service.Storage.Close()
+
+	for _, inventory := range []*skupb.Inventory{
+		{SkuId: 1, WarehouseId: 1, Quantity: 10, Type: skupb.Inventory_Finite},
+		{SkuId: 1, WarehouseId: 2, Quantity: 5, Type: skupb.Inventory_Finite},
+	} {
+		if _, err := service.New(ctx, &skupb.UpsertRequest{Name: "mavic", Inventory: inventory}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	quantity := func(warehouseId int64) int64 {
+		sku, err := service.Get(ctx, &skupb.GetRequest{Name: "mavic"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, inventory := range sku.GetInventory() {
+			if inventory.GetWarehouseId() == warehouseId {
+				return inventory.GetQuantity()
+			}
+		}
+		t.Fatalf("warehouse %d not found", warehouseId)
+		return 0
+	}
+
+	if warehouseId, err := service.Reserve(ctx, "mavic", 8); err != nil || warehouseId != 1 {
+		t.Errorf("expect reserved from warehouse 1, got %d, %v", warehouseId, err)
+	}
+	if warehouseId, err := service.Reserve(ctx, "mavic", 3); err != nil || warehouseId != 2 {
+		t.Errorf("expect reserved from warehouse 2, got %d, %v", warehouseId, err)
+	}
+	if _, err := service.Reserve(ctx, "mavic", 5); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expect out of stock, got %v", err)
+	}
+	if quantity(1) != 2 || quantity(2) != 2 {
+		t.Errorf("expect 2 left in each warehouse, got %d and %d", quantity(1), quantity(2))
+	}
+
+	if err := service.Release(ctx, "mavic", 1, 8); err != nil {
+		t.Fatal(err)
+	}
+	if quantity(1) != 10 {
+		t.Errorf("expect 10 after release, got %d", quantity(1))
+	}
+
+	var wg sync.WaitGroup
+	var reserved int64
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := service.Reserve(ctx, "mavic", 1); err == nil {
+				atomic.AddInt64(&reserved, 1)
+			} else if status.Code(err) != codes.ResourceExhausted {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if reserved != 12 || quantity(1) != 0 || quantity(2) != 0 {
+		t.Errorf("expect 12 reserved without oversell, got %d", reserved)
+	}
+
+	if _, err := service.Reserve(ctx, "not exist", 1); status.Code(err) != codes.NotFound {
+		t.Errorf("expect not found, got %v", err)
+	}
+
+	// inventory without type holding quantity is bounded by it, and stays
+	// bounded once its stock is taken
+	if _, err := service.New(ctx, &skupb.UpsertRequest{Name: "care", Inventory: &skupb.Inventory{SkuId: 2, WarehouseId: 1, Quantity: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Reserve(ctx, "care", 1); err != nil {
+		t.Errorf("expect reserved, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := service.Reserve(ctx, "care", 1); status.Code(err) != codes.ResourceExhausted {
+			t.Errorf("expect inventory without type out of stock, got %v", err)
+		}
+	}
+	if err := service.Release(ctx, "care", 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if sku, err := service.Get(ctx, &skupb.GetRequest{Name: "care"}); err != nil || sku.GetInventory()[0].GetQuantity() != 1 ||
+		sku.GetInventory()[0].GetType() != skupb.Inventory_Finite {
+		t.Errorf("expect released stock of finite inventory, got %v %v", sku, err)
+	}
+	if _, err := service.New(ctx, &skupb.UpsertRequest{Name: "goggles", Inventory: &skupb.Inventory{SkuId: 3, WarehouseId: 1, Type: skupb.Inventory_Infinite}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Reserve(ctx, "goggles", 100); err != nil {
+		t.Errorf("expect infinite inventory reserved, got %v", err)
+	}
+	if skupb.Inventory_Infinite != 0 || skupb.Inventory_Finite != 1 {
+		t.Error("inventory type numbers are part of the api")
+	}
 }
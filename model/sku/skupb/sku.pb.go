@@ -937,7 +937,7 @@ var file_sku_skupb_sku_proto_rawDesc = []byte{
 	0x01, 0x28, 0x01, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x22, 0x26, 0x0a, 0x04, 0x53, 0x6b,
 	0x75, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x73, 0x6b, 0x75, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
 	0x32, 0x0a, 0x2e, 0x73, 0x6b, 0x75, 0x70, 0x62, 0x2e, 0x53, 0x6b, 0x75, 0x52, 0x04, 0x73, 0x6b,
-	0x75, 0x73, 0x32, 0x84, 0x02, 0x0a, 0x0a, 0x53, 0x6b, 0x75, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x75, 0x73, 0x32, 0x89, 0x02, 0x0a, 0x0a, 0x53, 0x6b, 0x75, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
 	0x65, 0x12, 0x38, 0x0a, 0x03, 0x4e, 0x65, 0x77, 0x12, 0x14, 0x2e, 0x73, 0x6b, 0x75, 0x70, 0x62,
 	0x2e, 0x55, 0x70, 0x73, 0x65, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0a,
 	0x2e, 0x73, 0x6b, 0x75, 0x70, 0x62, 0x2e, 0x53, 0x6b, 0x75, 0x22, 0x0f, 0x82, 0xd3, 0xe4, 0x93,
@@ -948,16 +948,16 @@ var file_sku_skupb_sku_proto_rawDesc = []byte{
 	0x3a, 0x0a, 0x06, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x14, 0x2e, 0x73, 0x6b, 0x75, 0x70,
 	0x62, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
 	0x0c, 0x2e, 0x73, 0x6b, 0x75, 0x70, 0x62, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x0c, 0x82,
-	0xd3, 0xe4, 0x93, 0x02, 0x06, 0x2a, 0x04, 0x2f, 0x73, 0x6b, 0x75, 0x12, 0x4c, 0x0a, 0x0e, 0x47,
+	0xd3, 0xe4, 0x93, 0x02, 0x06, 0x2a, 0x04, 0x2f, 0x73, 0x6b, 0x75, 0x12, 0x51, 0x0a, 0x0e, 0x47,
 	0x65, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x53, 0x6b, 0x75, 0x73, 0x12, 0x1c, 0x2e,
 	0x73, 0x6b, 0x75, 0x70, 0x62, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74,
 	0x53, 0x6b, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0b, 0x2e, 0x73, 0x6b,
-	0x75, 0x70, 0x62, 0x2e, 0x53, 0x6b, 0x75, 0x73, 0x22, 0x0f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x09,
-	0x22, 0x04, 0x2f, 0x73, 0x6b, 0x75, 0x3a, 0x01, 0x2a, 0x42, 0x35, 0x5a, 0x33, 0x67, 0x69, 0x74,
-	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x78, 0x69, 0x64, 0x6f, 0x6e, 0x67, 0x63, 0x2f,
-	0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x5f, 0x65, 0x62, 0x65, 0x6e, 0x63, 0x68, 0x6d, 0x61, 0x72, 0x6b,
-	0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2f, 0x73, 0x6b, 0x75, 0x2f, 0x73, 0x6b, 0x75, 0x70, 0x62,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x75, 0x70, 0x62, 0x2e, 0x53, 0x6b, 0x75, 0x73, 0x22, 0x14, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0e,
+	0x12, 0x0c, 0x2f, 0x73, 0x6b, 0x75, 0x2f, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x42, 0x35,
+	0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x78, 0x69, 0x64,
+	0x6f, 0x6e, 0x67, 0x63, 0x2f, 0x6d, 0x6f, 0x6e, 0x67, 0x6f, 0x5f, 0x65, 0x62, 0x65, 0x6e, 0x63,
+	0x68, 0x6d, 0x61, 0x72, 0x6b, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2f, 0x73, 0x6b, 0x75, 0x2f,
+	0x73, 0x6b, 0x75, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
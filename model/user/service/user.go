@@ -18,19 +18,21 @@ package service
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"github.com/mitchellh/mapstructure"
 	log "github.com/sirupsen/logrus"
 	"github.com/xidongc-wish/mgo/bson"
 	"github.com/xidongc/mongo_ebenchmark/model/user/userpb"
 	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
 	"github.com/xidongc/mongo_ebenchmark/pkg/proxy"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const ns = "user"
 
 type Service struct {
-	Storage   proxy.Client
+	Storage   proxy.Storage
 	Amplifier cfg.Amplifier
 }
 
@@ -51,6 +53,7 @@ func (s Service) New(ctx context.Context, req *userpb.NewRequest) (user *userpb.
 	var desired bson.M
 	if err = mapstructure.Decode(reqUser, &desired); err != nil {
 		log.Error(err)
+		return nil, proxy.ToStatus(err)
 	}
 
 	param := proxy.FindModifyParam{
@@ -65,8 +68,11 @@ func (s Service) New(ctx context.Context, req *userpb.NewRequest) (user *userpb.
 	result, err := s.Storage.FindAndModify(ctx, &param)
 	if err != nil {
 		log.Error(err)
+		return nil, proxy.ToStatus(err)
+	}
+	if err = mapstructure.Decode(result, &user); err != nil {
+		return nil, proxy.ToStatus(err)
 	}
-	err = mapstructure.Decode(result, &user)
 	return
 }
 
@@ -80,16 +86,19 @@ func (s Service) Get(ctx context.Context, req *userpb.GetRequest) (user *userpb.
 
 	results, err := s.Storage.Find(ctx, param)
 
-	if err != nil || len(results) > 1 {
+	if err != nil {
 		log.Error(err)
-		return
+		return nil, proxy.ToStatus(err)
+	} else if len(results) > 1 {
+		return nil, status.Errorf(codes.FailedPrecondition, "duplicate user %s", req.GetNickname())
 	} else if len(results) == 0 {
-		return user, errors.New("no result found")
+		return nil, proxy.ToStatus(proxy.ErrNotFound)
 	}
 
 	err = mapstructure.Decode(results[0], &user)
 	if err != nil {
-		log.Fatal(err)
+		log.Error(err)
+		return nil, proxy.ToStatus(err)
 	}
 	return user, nil
 }
@@ -99,6 +108,7 @@ func (s Service) Deactivate(ctx context.Context, req *userpb.DeleteRequest) (use
 	user, err = s.Get(ctx, &userpb.GetRequest{Nickname: req.Nickname})
 	if err != nil {
 		log.Error(err)
+		return nil, err
 	}
 	user.Active = false
 
@@ -106,6 +116,7 @@ func (s Service) Deactivate(ctx context.Context, req *userpb.DeleteRequest) (use
 
 	if err = mapstructure.Decode(user, &desired); err != nil {
 		log.Error(err)
+		return nil, proxy.ToStatus(err)
 	}
 
 	params := &proxy.FindModifyParam{
@@ -120,13 +131,26 @@ func (s Service) Deactivate(ctx context.Context, req *userpb.DeleteRequest) (use
 	result, err := s.Storage.FindAndModify(ctx, params)
 	if err != nil {
 		log.Error(err)
+		return nil, proxy.ToStatus(err)
+	}
+	if err = mapstructure.Decode(result, &user); err != nil {
+		return nil, proxy.ToStatus(err)
 	}
-	err = mapstructure.Decode(result, &user)
 	return
 }
 
 // Create User Service client
-func NewClient(config *cfg.ProxyConfig, cancel context.CancelFunc) (client *proxy.Client) {
-	client, _ = proxy.NewClient(config, ns, cancel)
+func NewClient(config *cfg.ProxyConfig, cancel context.CancelFunc) (client proxy.Storage, err error) {
+	if client, err = proxy.NewStorage(config, ns, cancel); err != nil {
+		return nil, fmt.Errorf("%s: create storage error with: %w", ns, err)
+	}
+	return
+}
+
+// Create User Service client on connections shared by manager
+func NewSharedClient(manager *proxy.Manager) (client proxy.Storage, err error) {
+	if client, err = manager.Storage(ns); err != nil {
+		return nil, fmt.Errorf("%s: create storage error with: %w", ns, err)
+	}
 	return
 }
@@ -17,7 +17,6 @@
 package cfg
 
 import (
-	"github.com/xidongc-wish/mgo"
 	"github.com/xidongc/mongo_ebenchmark/mprpc"
 	"time"
 )
@@ -26,8 +25,20 @@ import (
 type Config struct {
 	ProxyConfig
 	AmplifyOptions
-	ServerPort int  `long:"server-port" default:"50053" description:" api server port"`
-	Turbo      bool `long:"turbo" description:"enable turbo mode"`
+	PaymentConfig
+	TraceConfig
+	MiddlewareConfig
+	HealthConfig
+	ServerPort        int    `long:"server-port" default:"50053" description:" api server port"`
+	GatewayPort       int    `long:"gateway-port" default:"50080" description:"http/json gateway port, disabled if 0"`
+	MetricsPort       int    `long:"metrics-port" default:"50090" description:"prometheus /metrics port, disabled if 0"`
+	TLSCert           string `long:"tls-cert" description:"api server certificate, serve tls if specified"`
+	TLSKey            string `long:"tls-key" description:"api server private key"`
+	TLSClientCA       string `long:"tls-client-ca" description:"ca verifying client certificates, require client certificates (mTLS) if specified"`
+	GatewayCA         string `long:"gateway-ca" description:"ca verifying api server certificate when gateway dials it, api server certificate itself if not specified"`
+	GatewayCert       string `long:"gateway-cert" description:"client certificate gateway presents to api server, required with --tls-client-ca"`
+	GatewayKey        string `long:"gateway-key" description:"client private key gateway presents to api server"`
+	GatewayServerName string `long:"gateway-server-name" description:"server name verified in api server certificate, loopback address if not specified"`
 }
 
 // AmplifyOptions
@@ -35,13 +46,60 @@ type Amplifier *AmplifyOptions
 
 // Proxy client cfg
 type ProxyConfig struct {
-	ProxyAddr    string `long:"proxy-addr" default:"127.0.0.1" description:"storage address"`
-	ProxyPort    int    `long:"proxy-port" default:"50051" description:"storage port"`
-	Secure       bool   `long:"https" description:"use tls to connect proxy backend"`
-	RpcTimeout   int64  `long:"rpc-timeout" default:"25000" description:"storage request timeout"`
-	BatchSize    int64  `short:"b" long:"batch" default:"10000" description:"batch size"`
-	ReadPref     int32  `short:"r" long:"read-pref" default:"2" description:"read preference"`
-	AllowPartial bool   `long:"partial" description:"allow partial"`
+	ProxyAddr          string            `long:"proxy-addr" default:"127.0.0.1" description:"storage address"`
+	ProxyPort          int               `long:"proxy-port" default:"50051" description:"storage port"`
+	Secure             bool              `long:"https" description:"use tls to connect proxy backend"`
+	ProxyCA            string            `long:"proxy-ca" description:"ca verifying proxy certificate, system roots if not specified"`
+	ProxyCert          string            `long:"proxy-cert" description:"client certificate presented to proxy for mTLS"`
+	ProxyKey           string            `long:"proxy-key" description:"client private key presented to proxy for mTLS"`
+	ProxyServerName    string            `long:"proxy-server-name" description:"server name verified in proxy certificate, proxy address if not specified"`
+	ProxyPoolSize      int               `long:"proxy-pool" default:"4" description:"grpc connections to proxy shared by all services"`
+	ProxyBalance       string            `long:"proxy-balance" default:"round-robin" choice:"round-robin" choice:"least-loaded" description:"how rpcs are spread over pooled proxy connections"`
+	RpcTimeout         int64             `long:"rpc-timeout" default:"25000" description:"storage request timeout"`
+	BatchSize          int64             `short:"b" long:"batch" description:"batch size, overrides consistency profile if specified"`
+	ReadPref           *int32            `short:"r" long:"read-pref" description:"read preference, overrides consistency profile if specified, eg: 0 for eventual"`
+	AllowPartial       bool              `long:"partial" description:"allow partial"`
+	Turbo              bool              `long:"turbo" description:"enable turbo mode, same as --profile turbo"`
+	ConsistencyProfile string            `long:"profile" choice:"turbo" choice:"safe" choice:"majority-read" choice:"causal" description:"consistency profile, turbo or safe based on --turbo if not specified"`
+	ServiceProfiles    map[string]string `long:"service-profile" description:"consistency profile per service, eg: sku:turbo, overrides --profile"`
+	Backend            string            `long:"backend" default:"proxy" choice:"proxy" choice:"mgo" choice:"memory" description:"storage backend"`
+	MongoURL           string            `long:"mongo-url" default:"mongodb://127.0.0.1:27017" description:"mongodb url used by mgo backend"`
+	AmpQueueSize       int               `long:"amp-queue" default:"16" description:"max pending amp jobs, new jobs are dropped once full"`
+	AmpWorkers         int               `long:"amp-workers" default:"2" description:"amp jobs running in background concurrently"`
+	ReportDir          string            `long:"report-dir" default:"results" description:"directory amp reports are stored in"`
+	ReportFormat       string            `long:"report-format" default:"json" choice:"json" choice:"csv" choice:"html" choice:"influx-line" choice:"prometheus" description:"amp report format besides json record"`
+}
+
+// Payment provider cfg
+type PaymentConfig struct {
+	PaymentProviders []string          `long:"payment-provider" default:"AliPay" default:"Mock" description:"payment providers enabled, eg: AliPay"`
+	PaymentFailover  map[string]string `long:"payment-failover" description:"secondary provider a provider fails over to on errors, eg: AliPay:Mock"`
+}
+
+// Tracing cfg, tracing is disabled if no exporter is specified
+type TraceConfig struct {
+	TraceOTLP    string            `long:"trace-otlp" description:"otlp/http endpoint spans are exported to, eg: http://127.0.0.1:4318"`
+	TraceHeaders map[string]string `long:"trace-header" description:"header sent with otlp export, eg: authorization:token"`
+	TraceFile    string            `long:"trace-file" description:"file spans are appended to as json lines"`
+	TraceSample  float64           `long:"trace-sample" default:"1" description:"ratio of traces sampled, between 0 and 1"`
+}
+
+// Api server middleware cfg, interceptors run in order of Middlewares,
+// the first one is outermost
+type MiddlewareConfig struct {
+	Middlewares    []string           `long:"middleware" default:"recovery" default:"request-id" default:"tracing" default:"metrics" default:"access-log" default:"limit" default:"deadline" default:"validate" choice:"recovery" choice:"request-id" choice:"tracing" choice:"metrics" choice:"access-log" choice:"limit" choice:"deadline" choice:"validate" description:"interceptors of api server in order, outermost first"`
+	MethodTimeouts map[string]float64 `long:"method-timeout" description:"deadline of a method in multiples of rpc-timeout, eg: orderpb.OrderService/Pay:3"`
+	MaxConcurrent  int                `long:"max-concurrent" default:"1000" description:"rpcs handled concurrently by api server, unlimited if 0"`
+	LimitWait      time.Duration      `long:"limit-wait" description:"time a rpc waits for others to finish once max-concurrent is reached, rejected with ResourceExhausted after"`
+}
+
+// Health check cfg of api server, a service is NOT_SERVING once its
+// storage fails HealthFailures probes in a row
+type HealthConfig struct {
+	HealthInterval  time.Duration `long:"health-interval" default:"10s" description:"interval storage of services is probed"`
+	HealthTimeout   time.Duration `long:"health-timeout" default:"2s" description:"timeout of a storage probe"`
+	HealthFailures  int           `long:"health-failures" default:"3" description:"failed probes in a row moving a service to NOT_SERVING"`
+	HealthSuccesses int           `long:"health-successes" default:"1" description:"succeeded probes in a row moving a service back to SERVING"`
 }
 
 // AmplifyOptions for amp
@@ -51,19 +109,26 @@ type AmplifyOptions struct {
 	TotalRequest uint          `long:"requests" default:"20" description:"total perf requests sent for amp"`
 	QPS          uint          `long:"qps" description:"qps used for amp"`
 	Timeout      time.Duration `long:"timeout" description:"timeout for amp request to backend"`
+	Duration     time.Duration `long:"duration" description:"run duration for amp, eg: 10m, overrides requests if specified"`
 	CPUs         uint          `long:"cpu" default:"1" description:"cpus used for amp"`
 }
 
 // Create default cfg
 func DefaultConfig() (config *ProxyConfig) {
 	config = &ProxyConfig{
-		ProxyAddr:    "127.0.0.1",
-		ProxyPort:    50051,
-		Secure:       false,
-		RpcTimeout:   25000,
-		BatchSize:    10000,
-		ReadPref:     int32(mgo.Primary),
-		AllowPartial: false,
+		ProxyAddr:     "127.0.0.1",
+		ProxyPort:     50051,
+		ProxyPoolSize: 4,
+		ProxyBalance:  "round-robin",
+		Secure:        false,
+		RpcTimeout:    25000,
+		AllowPartial:  false,
+		Backend:       "proxy",
+		MongoURL:      "mongodb://127.0.0.1:27017",
+		AmpQueueSize:  16,
+		AmpWorkers:    2,
+		ReportDir:     "results",
+		ReportFormat:  "json",
 	}
 	return
 }
@@ -88,6 +153,20 @@ func StressAmplifier() (amplifier *AmplifyOptions) {
 	return
 }
 
+// SoakAmplifier generate AmplifyOptions running given duration at given qps,
+// eg: SoakAmplifier(10*time.Minute, 500) for "10 minutes at 500 QPS"
+func SoakAmplifier(duration time.Duration, qps uint) (amplifier *AmplifyOptions) {
+	amplifier = &AmplifyOptions{
+		Connections: 10,
+		Concurrency: 50,
+		QPS:         qps,
+		Duration:    duration,
+		Timeout:     20 * time.Second,
+		CPUs:        1,
+	}
+	return
+}
+
 // Get AmplifyOptions based on given param
 func Amplifer(options AmplifyOptions) *AmplifyOptions {
 	return &options
@@ -98,7 +177,6 @@ func Amplifer(options AmplifyOptions) *AmplifyOptions {
 // Relevant documentation:
 //
 // merge fsync and J: https://jira.mongodb.org/browse/SERVER-11399
-//
 func GetTurboWriteOptions() (wOptions *mprpc.WriteOptions) {
 	wOptions = &mprpc.WriteOptions{
 		Writeconcern: 1,
@@ -114,7 +192,6 @@ func GetTurboWriteOptions() (wOptions *mprpc.WriteOptions) {
 // Relevant documentation:
 //
 // merge fsync and J: https://jira.mongodb.org/browse/SERVER-11399
-//
 func GetSafeWriteOptions() (wOptions *mprpc.WriteOptions) {
 	wOptions = &mprpc.WriteOptions{
 		Writetimeout: 0,
@@ -0,0 +1,157 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package cfg
+
+import (
+	"fmt"
+	"github.com/xidongc-wish/mgo"
+	"github.com/xidongc/mongo_ebenchmark/mprpc"
+	"sort"
+)
+
+// Consistency profile names
+const (
+	TurboProfile        = "turbo"
+	SafeProfile         = "safe"
+	MajorityReadProfile = "majority-read"
+	CausalProfile       = "causal"
+)
+
+// Profile is a named consistency preset applied to every proxy request,
+// it trades consistency for throughput in different steps:
+//
+//     turbo:         local read on nearest member, w:1 without journal
+//     safe:          linearizable read on primary, majority write with journal
+//     majority-read: majority read on secondaries, majority write with journal
+//     causal:        majority read and write, read on primary if available
+//
+// Relevant documentation:
+//
+//     https://docs.mongodb.com/manual/reference/read-concern/
+//     https://docs.mongodb.com/manual/reference/write-concern/
+//     https://docs.mongodb.com/manual/core/causal-consistency-read-write-concerns/
+//
+type Profile struct {
+	Name         string
+	ReadConcern  string
+	ReadPref     mgo.Mode
+	Prefetch     float64
+	BatchSize    int64
+	WriteOptions *mprpc.WriteOptions
+}
+
+// profiles registered by name, GetProfile returns a copy
+var profiles = map[string]func() *Profile{
+	TurboProfile: func() *Profile {
+		return &Profile{
+			Name:         TurboProfile,
+			ReadConcern:  "local",
+			ReadPref:     mgo.Nearest,
+			Prefetch:     0.75,
+			BatchSize:    10000,
+			WriteOptions: GetTurboWriteOptions(),
+		}
+	},
+	SafeProfile: func() *Profile {
+		return &Profile{
+			Name:         SafeProfile,
+			ReadConcern:  "linearizable",
+			ReadPref:     mgo.Primary,
+			Prefetch:     0.25,
+			BatchSize:    10000,
+			WriteOptions: GetSafeWriteOptions(),
+		}
+	},
+	MajorityReadProfile: func() *Profile {
+		return &Profile{
+			Name:        MajorityReadProfile,
+			ReadConcern: "majority",
+			ReadPref:    mgo.SecondaryPreferred,
+			Prefetch:    0.5,
+			BatchSize:   5000,
+			WriteOptions: &mprpc.WriteOptions{
+				Writetimeout: 5000,
+				Writemode:    "majority",
+				J:            true,
+			},
+		}
+	},
+	CausalProfile: func() *Profile {
+		return &Profile{
+			Name:        CausalProfile,
+			ReadConcern: "majority",
+			ReadPref:    mgo.PrimaryPreferred,
+			Prefetch:    0.25,
+			BatchSize:   1000,
+			WriteOptions: &mprpc.WriteOptions{
+				Writetimeout: 5000,
+				Writemode:    "majority",
+				J:            true,
+			},
+		}
+	},
+}
+
+// GetProfile returns consistency profile of given name
+func GetProfile(name string) (*Profile, error) {
+	profile, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown consistency profile %q, expect one of %v", name, ProfileNames())
+	}
+	return profile(), nil
+}
+
+// ProfileNames returns names of all consistency profiles
+func ProfileNames() (names []string) {
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// Profile returns consistency profile used by given namespace, it is
+// looked up in ServiceProfiles, then Profile, then turbo or safe based
+// on Turbo flag, ReadPref and BatchSize override profile if specified
+func (config *ProxyConfig) Profile(namespace string) (profile *Profile, err error) {
+	name, ok := config.ServiceProfiles[namespace]
+	if !ok {
+		name = config.ConsistencyProfile
+	}
+	if name == "" && config.Turbo {
+		name = TurboProfile
+	} else if name == "" {
+		name = SafeProfile
+	}
+	return config.NamedProfile(name)
+}
+
+// NamedProfile returns consistency profile of given name, with ReadPref
+// and BatchSize overridden if specified in config, ReadPref is a pointer
+// since mgo.Eventual is 0 and nil means unset
+func (config *ProxyConfig) NamedProfile(name string) (profile *Profile, err error) {
+	if profile, err = GetProfile(name); err != nil {
+		return
+	}
+	if config.ReadPref != nil {
+		profile.ReadPref = mgo.Mode(*config.ReadPref)
+	}
+	if config.BatchSize > 0 {
+		profile.BatchSize = config.BatchSize
+	}
+	return
+}
@@ -0,0 +1,82 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package cfg
+
+import (
+	"github.com/xidongc-wish/mgo"
+	"testing"
+)
+
+// Test on resolving consistency profile per service
+func TestProfile(t *testing.T) {
+	config := DefaultConfig()
+	config.ServiceProfiles = map[string]string{"sku": CausalProfile}
+
+	cases := []struct {
+		turbo     bool
+		profile   string
+		namespace string
+		expected  string
+	}{
+		{false, "", "order", SafeProfile},
+		{true, "", "order", TurboProfile},
+		{true, MajorityReadProfile, "order", MajorityReadProfile},
+		{true, MajorityReadProfile, "sku", CausalProfile},
+	}
+	for _, c := range cases {
+		config.Turbo = c.turbo
+		config.ConsistencyProfile = c.profile
+		profile, err := config.Profile(c.namespace)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if profile.Name != c.expected {
+			t.Errorf("expect %s for %+v, got %s", c.expected, c, profile.Name)
+		}
+	}
+
+	readPref := int32(mgo.Secondary)
+	config.ReadPref = &readPref
+	config.BatchSize = 10
+	profile, err := config.NamedProfile(TurboProfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.ReadPref != mgo.Secondary || profile.BatchSize != 10 {
+		t.Errorf("expect read pref and batch size overridden, got %+v", profile)
+	}
+
+	// eventual is zero value of mgo.Mode, still an override once set
+	readPref = int32(mgo.Eventual)
+	if profile, err = config.NamedProfile(TurboProfile); err != nil {
+		t.Fatal(err)
+	}
+	if profile.ReadPref != mgo.Eventual {
+		t.Errorf("expect read pref overridden by eventual, got %+v", profile)
+	}
+	config.ReadPref = nil
+	if profile, err = config.NamedProfile(TurboProfile); err != nil {
+		t.Fatal(err)
+	}
+	if profile.ReadPref != mgo.Nearest {
+		t.Errorf("expect read pref of profile if unset, got %+v", profile)
+	}
+
+	if _, err := config.NamedProfile("eventual"); err == nil {
+		t.Error("expect error on unknown profile")
+	}
+}
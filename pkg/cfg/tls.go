@@ -0,0 +1,112 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package cfg
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+)
+
+// NewClientTLS creates tls config of grpc client, server certificate is
+// verified by ca, or system roots if ca is empty, cert and key are
+// presented to server for mTLS if specified
+func NewClientTLS(ca string, cert string, key string, serverName string) (*tls.Config, error) {
+	config := &tls.Config{ServerName: serverName}
+	if ca != "" {
+		pool, err := loadCertPool(ca)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+	if cert != "" || key != "" {
+		certificate, err := tls.LoadX509KeyPair(cert, key)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate error: %s", err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+	return config, nil
+}
+
+// NewServerTLS creates tls config of grpc server, client certificates
+// are required and verified by clientCA if specified
+func NewServerTLS(cert string, key string, clientCA string) (*tls.Config, error) {
+	certificate, err := tls.LoadX509KeyPair(cert, key)
+	if err != nil {
+		return nil, fmt.Errorf("load server certificate error: %s", err)
+	}
+	config := &tls.Config{Certificates: []tls.Certificate{certificate}}
+	if clientCA != "" {
+		pool, err := loadCertPool(clientCA)
+		if err != nil {
+			return nil, err
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// ProxyTLS returns tls config dialing proxy, nil if Secure is not set
+func (c *ProxyConfig) ProxyTLS() (*tls.Config, error) {
+	if !c.Secure {
+		return nil, nil
+	}
+	return NewClientTLS(c.ProxyCA, c.ProxyCert, c.ProxyKey, c.ProxyServerName)
+}
+
+// ServerTLS returns tls config of api server, nil if TLSCert is not set
+func (c *Config) ServerTLS() (*tls.Config, error) {
+	if c.TLSCert == "" {
+		return nil, nil
+	}
+	return NewServerTLS(c.TLSCert, c.TLSKey, c.TLSClientCA)
+}
+
+// GatewayTLS returns tls config gateway dials api server with, nil if
+// TLSCert is not set. api server certificate is verified by GatewayCA,
+// or trusted as root itself if not specified, GatewayCert and GatewayKey
+// are presented for mTLS, they are required once TLSClientCA is set
+func (c *Config) GatewayTLS() (*tls.Config, error) {
+	if c.TLSCert == "" {
+		return nil, nil
+	}
+	if c.TLSClientCA != "" && c.GatewayCert == "" {
+		return nil, fmt.Errorf("gateway certificate is required to dial api server requiring client certificates")
+	}
+	ca := c.GatewayCA
+	if ca == "" {
+		ca = c.TLSCert
+	}
+	return NewClientTLS(ca, c.GatewayCert, c.GatewayKey, c.GatewayServerName)
+}
+
+// loadCertPool loads pem encoded certificates of file
+func loadCertPool(path string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read ca error: %s", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificate found in %s", path)
+	}
+	return pool, nil
+}
@@ -0,0 +1,201 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package cfg
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCert signs a certificate of name by parent, self-signed if parent
+// is nil, and writes it with its key as <name>.pem and <name>-key.pem
+func writeCert(t *testing.T, dir string, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	if err = ioutil.WriteFile(filepath.Join(dir, name+".pem"), certPem, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(dir, name+"-key.pem"), keyPem, 0600); err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+// handshake runs tls handshake between client and server on loopback
+func handshake(client *tls.Config, server *tls.Config) error {
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", server)
+	if err != nil {
+		return err
+	}
+	defer lis.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close()
+		serverErr <- conn.(*tls.Conn).Handshake()
+	}()
+
+	conn, err := tls.Dial("tcp", lis.Addr().String(), client)
+	if err == nil {
+		// client certificate is verified by server after client finished
+		err = <-serverErr
+		conn.Close()
+		return err
+	}
+	<-serverErr
+	return err
+}
+
+func TestTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ca, caKey := writeCert(t, dir, "ca", nil, nil)
+	writeCert(t, dir, "server", ca, caKey)
+	writeCert(t, dir, "client", ca, caKey)
+	file := func(name string) string {
+		return filepath.Join(dir, name)
+	}
+
+	config := &Config{TLSCert: file("server.pem"), TLSKey: file("server-key.pem")}
+	serverTLS, err := config.ServerTLS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientTLS, err := NewClientTLS(file("ca.pem"), "", "", "server")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = handshake(clientTLS, serverTLS); err != nil {
+		t.Errorf("expect tls handshake, got %v", err)
+	}
+
+	config.TLSClientCA = file("ca.pem")
+	mutualTLS, err := config.ServerTLS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = handshake(clientTLS, mutualTLS); err == nil {
+		t.Error("expect client without certificate rejected")
+	}
+	clientTLS, err = NewClientTLS(file("ca.pem"), file("client.pem"), file("client-key.pem"), "server")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = handshake(clientTLS, mutualTLS); err != nil {
+		t.Errorf("expect mtls handshake, got %v", err)
+	}
+
+	if tlsConfig, err := (&Config{}).ServerTLS(); tlsConfig != nil || err != nil {
+		t.Errorf("expect no tls without certificate, got %v, %v", tlsConfig, err)
+	}
+	if tlsConfig, err := (&ProxyConfig{ProxyCA: file("ca.pem")}).ProxyTLS(); tlsConfig != nil || err != nil {
+		t.Errorf("expect no proxy tls without secure, got %v, %v", tlsConfig, err)
+	}
+	if _, err := (&ProxyConfig{Secure: true, ProxyCA: file("server-key.pem")}).ProxyTLS(); err == nil {
+		t.Error("expect error on ca without certificate")
+	}
+
+	// gateway trusts api server certificate itself, or ca if specified
+	config = &Config{TLSCert: file("server.pem"), TLSKey: file("server-key.pem"), GatewayServerName: "server"}
+	for _, ca := range []string{"", file("ca.pem")} {
+		config.GatewayCA = ca
+		gatewayTLS, err := config.GatewayTLS()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = handshake(gatewayTLS, serverTLS); err != nil {
+			t.Errorf("expect gateway tls handshake with ca %q, got %v", ca, err)
+		}
+	}
+	config.GatewayCA = file("client.pem")
+	gatewayTLS, err := config.GatewayTLS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = handshake(gatewayTLS, serverTLS); err == nil {
+		t.Error("expect api server certificate rejected by other ca")
+	}
+	config.GatewayCA, config.GatewayServerName = "", "other"
+	if gatewayTLS, err = config.GatewayTLS(); err != nil {
+		t.Fatal(err)
+	}
+	if err = handshake(gatewayTLS, serverTLS); err == nil {
+		t.Error("expect api server certificate rejected on server name mismatch")
+	}
+
+	config.GatewayServerName, config.TLSClientCA = "server", file("ca.pem")
+	if _, err := config.GatewayTLS(); err == nil {
+		t.Error("expect gateway certificate required by mtls")
+	}
+	config.GatewayCert, config.GatewayKey = file("client.pem"), file("client-key.pem")
+	if gatewayTLS, err = config.GatewayTLS(); err != nil {
+		t.Fatal(err)
+	}
+	if err = handshake(gatewayTLS, mutualTLS); err != nil {
+		t.Errorf("expect gateway mtls handshake, got %v", err)
+	}
+}
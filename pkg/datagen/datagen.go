@@ -0,0 +1,411 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+// Package datagen generates deterministic e-commerce documents for
+// seeding benchmark collections, the same seed always produces the same
+// documents regardless of batch size or number of loaders
+package datagen
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/mitchellh/mapstructure"
+	"github.com/xidongc-wish/mgo/bson"
+	"github.com/xidongc/mongo_ebenchmark/model/order/orderpb"
+	"github.com/xidongc/mongo_ebenchmark/model/payment/paymentpb"
+	"github.com/xidongc/mongo_ebenchmark/model/product/productpb"
+	"github.com/xidongc/mongo_ebenchmark/model/sku/skupb"
+	"github.com/xidongc/mongo_ebenchmark/model/user/userpb"
+	"math/rand"
+	"time"
+)
+
+// Kind of generated document, it is also the namespace documents are
+// loaded into
+type Kind string
+
+const (
+	Product Kind = "product"
+	Sku     Kind = "sku"
+	User    Kind = "user"
+	Order   Kind = "order"
+)
+
+// Kinds in loading order
+var Kinds = []Kind{Product, Sku, User, Order}
+
+// epoch is the earliest created timestamp of generated documents
+var epoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+const year = int64(365 * 24 * time.Hour / time.Second)
+
+// Options of generated data set
+type Options struct {
+	Seed           int64   `long:"seed" description:"random seed, same seed generates same documents" default:"1"`
+	Products       int     `long:"products" description:"number of products" default:"100000"`
+	MinSkus        int     `long:"min-skus" description:"min skus per product" default:"1"`
+	MaxSkus        int     `long:"max-skus" description:"max skus per product" default:"8"`
+	Users          int     `long:"users" description:"number of users" default:"100000"`
+	Orders         int     `long:"orders" description:"number of orders" default:"1000000"`
+	MaxItems       int     `long:"max-items" description:"max items per order" default:"5"`
+	ZipfS          float64 `long:"zipf-s" description:"zipf exponent of product popularity, must be > 1" default:"1.1"`
+	ZipfV          float64 `long:"zipf-v" description:"zipf offset of product popularity, must be >= 1" default:"1"`
+	MetadataKeys   int     `long:"metadata-keys" description:"metadata entries per document" default:"4"`
+	MetadataValues int     `long:"metadata-values" description:"distinct values per metadata key" default:"100"`
+	Images         int     `long:"images" description:"images per product" default:"2"`
+	ImageSize      int     `long:"image-size" description:"image payload size in bytes" default:"1024"`
+	Warehouses     int     `long:"warehouses" description:"number of warehouses holding inventory" default:"8"`
+}
+
+// DefaultOptions returns a small data set, which is handy for tests
+func DefaultOptions() *Options {
+	return &Options{
+		Seed:           1,
+		Products:       1000,
+		MinSkus:        1,
+		MaxSkus:        8,
+		Users:          1000,
+		Orders:         10000,
+		MaxItems:       5,
+		ZipfS:          1.1,
+		ZipfV:          1,
+		MetadataKeys:   4,
+		MetadataValues: 100,
+		Images:         2,
+		ImageSize:      1024,
+		Warehouses:     8,
+	}
+}
+
+// Validate options
+func (o *Options) Validate() error {
+	switch {
+	case o.Products <= 0:
+		return errors.New("products must be positive")
+	case o.MinSkus <= 0 || o.MaxSkus < o.MinSkus || o.MaxSkus > 1000:
+		return errors.New("skus per product must be within 1 <= min-skus <= max-skus <= 1000")
+	case o.Users < 0 || o.Orders < 0:
+		return errors.New("users and orders must not be negative")
+	case o.Orders > 0 && (o.Users == 0 || o.MaxItems <= 0):
+		return errors.New("orders require users and max-items")
+	case o.ZipfS <= 1 || o.ZipfV < 1:
+		return errors.New("zipf-s must be > 1 and zipf-v must be >= 1")
+	case o.MetadataKeys < 0 || o.MetadataValues <= 0:
+		return errors.New("metadata-keys must not be negative and metadata-values must be positive")
+	case o.Images < 0 || o.ImageSize < 0 || o.Warehouses <= 0:
+		return errors.New("images, image-size and warehouses must not be negative")
+	}
+	return nil
+}
+
+// ProductID returns id of i-th product
+func ProductID(i int) string {
+	return fmt.Sprintf("product-%d", i)
+}
+
+// SkuName returns name of sku-th sku of given product
+func SkuName(product int, sku int) string {
+	return fmt.Sprintf("sku-%d-%d", product, sku)
+}
+
+// SkuID returns inventory sku id of given sku
+func SkuID(product int, sku int) int64 {
+	return int64(product*1000 + sku)
+}
+
+// SkuPrice returns price of given sku in cents
+func SkuPrice(product int, sku int) int64 {
+	return int64(100 + (product*31+sku*7)%10000)
+}
+
+// Nickname returns nickname of i-th user
+func Nickname(i int) string {
+	return fmt.Sprintf("user-%d", i)
+}
+
+// OrderID returns id of i-th order
+func OrderID(i int) string {
+	return fmt.Sprintf("order-%d", i)
+}
+
+// Generator generates documents by index, it is safe for concurrent use
+// since every document gets its own random source
+type Generator struct {
+	options Options
+}
+
+// NewGenerator validates options and creates a Generator
+func NewGenerator(options *Options) (*Generator, error) {
+	if options == nil {
+		options = DefaultOptions()
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	return &Generator{options: *options}, nil
+}
+
+// Count returns number of documents of given kind
+func (g *Generator) Count(kind Kind) int {
+	switch kind {
+	case Product:
+		return g.options.Products
+	case Sku:
+		count := 0
+		for p := 0; p < g.options.Products; p++ {
+			count += g.SkuCount(p)
+		}
+		return count
+	case User:
+		return g.options.Users
+	case Order:
+		return g.options.Orders
+	}
+	return 0
+}
+
+// SkuCount returns number of skus of given product
+func (g *Generator) SkuCount(product int) int {
+	spread := g.options.MaxSkus - g.options.MinSkus + 1
+	return g.options.MinSkus + g.rand("skus", product).Intn(spread)
+}
+
+// Documents calls fn with every document of given kind in index order,
+// iteration stops once fn returns an error
+func (g *Generator) Documents(kind Kind, fn func(doc interface{}) error) (err error) {
+	switch kind {
+	case Product:
+		for i := 0; i < g.options.Products && err == nil; i++ {
+			err = fn(g.Product(i))
+		}
+	case Sku:
+		for p := 0; p < g.options.Products && err == nil; p++ {
+			for s, count := 0, g.SkuCount(p); s < count && err == nil; s++ {
+				err = fn(g.SkuDoc(p, s))
+			}
+		}
+	case User:
+		for i := 0; i < g.options.Users && err == nil; i++ {
+			err = fn(g.UserDoc(i))
+		}
+	case Order:
+		for i := 0; i < g.options.Orders && err == nil; i++ {
+			err = fn(g.Order(i))
+		}
+	default:
+		err = fmt.Errorf("unknown kind %s", kind)
+	}
+	return
+}
+
+// Product generates i-th product
+func (g *Generator) Product(i int) *productpb.Product {
+	rng := g.rand(Product, i)
+	created := epoch + rng.Int63n(year)
+
+	product := &productpb.Product{
+		Id:          ProductID(i),
+		Name:        fmt.Sprintf("%s %s %d", pick(rng, adjectives), pick(rng, nouns), i),
+		Active:      rng.Intn(10) > 0,
+		Attributes:  []string{"color", "size"},
+		Description: sentence(rng, 12),
+		Metadata:    g.metadata(rng),
+		Shippable:   rng.Intn(5) > 0,
+		Url:         fmt.Sprintf("https://ebenchmark.io/products/%d", i),
+		Type:        productpb.Category(rng.Intn(len(productpb.Category_name))),
+		Created:     created,
+		Updated:     created + rng.Int63n(year),
+	}
+	for j := 0; j < g.options.Images; j++ {
+		product.Images = append(product.Images, g.image(rng))
+	}
+	return product
+}
+
+// Sku generates sku-th sku of given product
+func (g *Generator) Sku(product int, sku int) *skupb.Sku {
+	rng := g.rand(Sku, product*g.options.MaxSkus+sku)
+	created := epoch + rng.Int63n(year)
+	name := SkuName(product, sku)
+
+	var inventory []*skupb.Inventory
+	for w, n := rng.Intn(g.options.Warehouses), 1+rng.Intn(3); n > 0; n-- {
+		inventory = append(inventory, &skupb.Inventory{
+			SkuId:       SkuID(product, sku),
+			WarehouseId: int64(w % g.options.Warehouses),
+			Quantity:    rng.Int63n(1000),
+			Type:        skupb.Inventory_Finite,
+		})
+		w++
+	}
+
+	return &skupb.Sku{
+		Name:      name,
+		Price:     uint64(SkuPrice(product, sku)),
+		Currency:  paymentpb.Currency_USD,
+		Active:    rng.Intn(10) > 0,
+		ProductId: ProductID(product),
+		Metadata:  g.metadata(rng),
+		Attributes: map[string]string{
+			"color": pick(rng, colors),
+			"size":  pick(rng, sizes),
+		},
+		Image: g.image(rng),
+		PackageDimensions: &skupb.PackageDimensions{
+			Height: 1 + rng.Float64()*50,
+			Length: 1 + rng.Float64()*50,
+			Weight: 0.1 + rng.Float64()*20,
+			Width:  1 + rng.Float64()*50,
+		},
+		Inventory:    inventory,
+		HasBattery:   rng.Intn(10) == 0,
+		HasLiquid:    rng.Intn(20) == 0,
+		HasSensitive: rng.Intn(50) == 0,
+		Description:  sentence(rng, 8),
+		SkuLabel:     name,
+		Supplier:     fmt.Sprintf("supplier-%d", rng.Intn(100)),
+		Created:      created,
+		Updated:      created + rng.Int63n(year),
+	}
+}
+
+// SkuDoc returns sku as stored by sku service, keyed by go field names
+func (g *Generator) SkuDoc(product int, sku int) bson.M {
+	return decode(g.Sku(product, sku))
+}
+
+// User generates i-th user
+func (g *Generator) User(i int) *userpb.User {
+	rng := g.rand(User, i)
+	created := epoch + rng.Int63n(year)
+	nick := Nickname(i)
+
+	return &userpb.User{
+		Name:     fmt.Sprintf("%s %s", pick(rng, firstNames), pick(rng, lastNames)),
+		Nickname: nick,
+		Email:    fmt.Sprintf("%s@ebenchmark.io", nick),
+		Active:   rng.Intn(20) > 0,
+		Balance:  rng.Int63n(1000000),
+		Currency: paymentpb.Currency_USD,
+		Image:    g.image(rng),
+		Pwd:      fmt.Sprintf("%016x", rng.Uint64()),
+		Metadata: g.metadata(rng),
+		Created:  created,
+		Updated:  created + rng.Int63n(year),
+	}
+}
+
+// UserDoc returns user as stored by user service, keyed by go field names
+func (g *Generator) UserDoc(i int) bson.M {
+	return decode(g.User(i))
+}
+
+// Order generates i-th order, products of items follow zipf distribution
+// so product-0 is the most popular one
+func (g *Generator) Order(i int) *orderpb.Order {
+	rng := g.rand(Order, i)
+	popularity := rand.NewZipf(rng, g.options.ZipfS, g.options.ZipfV, uint64(g.options.Products-1))
+	created := epoch + rng.Int63n(year)
+	customer := rng.Intn(g.options.Users)
+
+	order := &orderpb.Order{
+		Id:         OrderID(i),
+		CustomerId: uint64(customer),
+		Currency:   paymentpb.Currency_USD,
+		Status:     orderpb.OrderStatus(rng.Intn(len(orderpb.OrderStatus_name))),
+		Shipping: &orderpb.Shipping{
+			Name:  Nickname(customer),
+			Phone: fmt.Sprintf("555-%04d", rng.Intn(10000)),
+			Address: &orderpb.Shipping_Address{
+				Line1:      fmt.Sprintf("%d %s St", 1+rng.Intn(9999), pick(rng, lastNames)),
+				City:       pick(rng, cities),
+				Country:    "US",
+				PostalCode: fmt.Sprintf("%05d", rng.Intn(100000)),
+			},
+			Carrier: pick(rng, carriers),
+		},
+		Destination:   pick(rng, cities),
+		Metadata:      g.metadata(rng),
+		InvoiceNumber: int64(i),
+		Created:       created,
+		Updated:       created + rng.Int63n(30*24*3600),
+	}
+	for n := 1 + rng.Intn(g.options.MaxItems); n > 0; n-- {
+		product := int(popularity.Uint64())
+		sku := rng.Intn(g.SkuCount(product))
+		quantity := 1 + rng.Int63n(3)
+		price := SkuPrice(product, sku)
+		order.Items = append(order.Items, &orderpb.Item{
+			ProductId: ProductID(product),
+			Name:      SkuName(product, sku),
+			Quantity:  quantity,
+			Amount:    price,
+			Currency:  paymentpb.Currency_USD,
+			Type:      orderpb.ItemType_product,
+		})
+		order.Amount += uint64(price * quantity)
+	}
+	return order
+}
+
+// rand returns random source of i-th document of given kind
+func (g *Generator) rand(kind Kind, i int) *rand.Rand {
+	return rand.New(rand.NewSource(mix(g.options.Seed, string(kind), i)))
+}
+
+// metadata returns MetadataKeys entries, each value drawn from
+// MetadataValues distinct values
+func (g *Generator) metadata(rng *rand.Rand) map[string]string {
+	if g.options.MetadataKeys == 0 {
+		return nil
+	}
+	metadata := make(map[string]string, g.options.MetadataKeys)
+	for k := 0; k < g.options.MetadataKeys; k++ {
+		metadata[fmt.Sprintf("key-%d", k)] = fmt.Sprintf("value-%d", rng.Intn(g.options.MetadataValues))
+	}
+	return metadata
+}
+
+// image returns an ImageSize bytes payload encoded as data url
+func (g *Generator) image(rng *rand.Rand) string {
+	if g.options.ImageSize == 0 {
+		return ""
+	}
+	payload := make([]byte, g.options.ImageSize)
+	_, _ = rng.Read(payload)
+	return "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(payload)
+}
+
+// mix derives a seed from seed, kind and index with splitmix64
+func mix(seed int64, kind string, i int) int64 {
+	h := uint64(seed)
+	for _, c := range kind {
+		h = h*31 + uint64(c)
+	}
+	h += uint64(i) * 0x9e3779b97f4a7c15
+	h = (h ^ (h >> 30)) * 0xbf58476d1ce4e5b9
+	h = (h ^ (h >> 27)) * 0x94d049bb133111eb
+	return int64(h ^ (h >> 31))
+}
+
+// decode converts document into bson.M keyed by go field names, the
+// same way services store it
+func decode(doc interface{}) bson.M {
+	var m bson.M
+	_ = mapstructure.Decode(doc, &m)
+	return m
+}
@@ -0,0 +1,137 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package datagen
+
+import (
+	"context"
+	"github.com/xidongc-wish/mgo/bson"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"github.com/xidongc/mongo_ebenchmark/pkg/proxy"
+	"reflect"
+	"testing"
+)
+
+// Test same seed generates same documents and different seed does not
+func TestDeterministic(t *testing.T) {
+	a, err := NewGenerator(DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := NewGenerator(DefaultOptions())
+	options := DefaultOptions()
+	options.Seed = 2
+	c, _ := NewGenerator(options)
+
+	if !reflect.DeepEqual(a.Product(42), b.Product(42)) || !reflect.DeepEqual(a.Order(7), b.Order(7)) {
+		t.Error("expect same documents with same seed")
+	}
+	if reflect.DeepEqual(a.Sku(3, 0), c.Sku(3, 0)) {
+		t.Error("expect different documents with different seed")
+	}
+
+	sku := a.SkuDoc(3, 0)
+	if sku["Name"] != SkuName(3, 0) || sku["ProductId"] != ProductID(3) {
+		t.Errorf("unexpected sku document %v", sku)
+	}
+	if user := a.UserDoc(5); user["Nickname"] != Nickname(5) {
+		t.Errorf("unexpected user document %v", user)
+	}
+}
+
+// Test configured distributions
+func TestDistribution(t *testing.T) {
+	options := DefaultOptions()
+	options.Orders = 2000
+	options.ImageSize = 300
+	options.MetadataKeys = 3
+	g, err := NewGenerator(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	popularity := make(map[string]int)
+	for i := 0; i < options.Orders; i++ {
+		order := g.Order(i)
+		var amount uint64
+		for _, item := range order.Items {
+			popularity[item.ProductId]++
+			amount += uint64(item.Amount * item.Quantity)
+		}
+		if amount != order.Amount {
+			t.Fatalf("expect order amount %d, got %d", amount, order.Amount)
+		}
+	}
+	if popularity[ProductID(0)] <= 10*popularity[ProductID(options.Products/2)] {
+		t.Errorf("expect zipf skew, got %d vs %d", popularity[ProductID(0)], popularity[ProductID(options.Products/2)])
+	}
+
+	for p := 0; p < 50; p++ {
+		if n := g.SkuCount(p); n < options.MinSkus || n > options.MaxSkus {
+			t.Errorf("sku count %d out of range", n)
+		}
+	}
+	product := g.Product(1)
+	if len(product.Images) != options.Images || len(product.Metadata) != options.MetadataKeys {
+		t.Errorf("unexpected product %+v", product)
+	}
+	// base64 of 300 bytes plus data url prefix
+	if len(product.Images[0]) != len("data:image/jpeg;base64,")+400 {
+		t.Errorf("unexpected image size %d", len(product.Images[0]))
+	}
+
+	options.ZipfS = 1
+	if _, err := NewGenerator(options); err == nil {
+		t.Error("expect invalid zipf-s error")
+	}
+}
+
+// Test loader inserts every document into its namespace
+func TestLoader(t *testing.T) {
+	options := DefaultOptions()
+	options.Products, options.Users, options.Orders = 20, 10, 30
+	g, err := NewGenerator(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := cfg.DefaultConfig()
+	config.Backend = proxy.MemoryBackend
+
+	storages := make(map[Kind]proxy.Storage)
+	for _, kind := range Kinds {
+		storage, err := proxy.NewStorage(config, "datagen_test_"+string(kind), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer storage.Close()
+		storages[kind] = storage
+	}
+
+	loader := &Loader{Generator: g, Storages: storages, BatchSize: 7, Workers: 3}
+	loaded, err := loader.Load(context.Background(), Kinds...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, kind := range Kinds {
+		count, err := storages[kind].Count(context.Background(), &proxy.QueryParam{Filter: bson.M{}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if loaded[kind] != g.Count(kind) || int(count) != g.Count(kind) {
+			t.Errorf("%s: expect %d documents, loaded %d, stored %d", kind, g.Count(kind), loaded[kind], count)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package datagen
+
+import (
+	"context"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/xidongc/mongo_ebenchmark/pkg/proxy"
+	"sync"
+	"sync/atomic"
+)
+
+// Loader bulk inserts generated documents, each kind goes to its own
+// storage, which is normally a proxy.Client of the kind namespace
+type Loader struct {
+	Generator *Generator
+	Storages  map[Kind]proxy.Storage
+	BatchSize int
+	Workers   int
+	Profile   string
+}
+
+// Load inserts all documents of given kinds in order, returns number of
+// documents inserted per kind
+func (l *Loader) Load(ctx context.Context, kinds ...Kind) (loaded map[Kind]int, err error) {
+	loaded = make(map[Kind]int)
+	for _, kind := range kinds {
+		storage, ok := l.Storages[kind]
+		if !ok {
+			return loaded, fmt.Errorf("no storage for %s", kind)
+		}
+		if loaded[kind], err = l.load(ctx, kind, storage); err != nil {
+			return loaded, fmt.Errorf("load %s error with: %w", kind, err)
+		}
+	}
+	return
+}
+
+// load sends batches of one kind to workers, the first insert error
+// cancels remaining batches
+func (l *Loader) load(ctx context.Context, kind Kind, storage proxy.Storage) (int, error) {
+	batchSize, workers := l.BatchSize, l.Workers
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+	total := l.Generator.Count(kind)
+	log.Infof("loading %d %s documents", total, kind)
+
+	// log progress every 100 batches
+	progress := int64(batchSize * 100)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		inserted int64
+		wg       sync.WaitGroup
+		once     sync.Once
+		loadErr  error
+	)
+	batches := make(chan []interface{}, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				err := storage.Insert(ctx, &proxy.InsertParam{Docs: batch, Profile: l.Profile})
+				if err != nil {
+					once.Do(func() {
+						loadErr = err
+						cancel()
+					})
+					continue
+				}
+				n := atomic.AddInt64(&inserted, int64(len(batch)))
+				if (n-int64(len(batch)))/progress != n/progress {
+					log.Infof("%s: %d/%d documents loaded", kind, n, total)
+				}
+			}
+		}()
+	}
+
+	batch := make([]interface{}, 0, batchSize)
+	err := l.Generator.Documents(kind, func(doc interface{}) error {
+		batch = append(batch, doc)
+		if len(batch) < batchSize {
+			return nil
+		}
+		select {
+		case batches <- batch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		batch = make([]interface{}, 0, batchSize)
+		return nil
+	})
+	if err == nil && len(batch) > 0 {
+		select {
+		case batches <- batch:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+	close(batches)
+	wg.Wait()
+
+	if loadErr != nil {
+		err = loadErr
+	}
+	return int(inserted), err
+}
@@ -0,0 +1,51 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package datagen
+
+import (
+	"math/rand"
+	"strings"
+)
+
+var (
+	adjectives = []string{"smart", "compact", "classic", "portable", "wireless", "organic", "ultra", "vintage", "premium", "foldable"}
+	nouns      = []string{"drone", "camera", "headphone", "backpack", "lamp", "kettle", "sneaker", "watch", "speaker", "jacket"}
+	colors     = []string{"black", "white", "red", "blue", "green", "grey", "pink"}
+	sizes      = []string{"XS", "S", "M", "L", "XL", "XXL"}
+	firstNames = []string{"alex", "sam", "jordan", "taylor", "casey", "riley", "morgan", "jamie", "quinn", "avery"}
+	lastNames  = []string{"smith", "chen", "garcia", "kim", "mueller", "rossi", "tanaka", "silva", "novak", "brown"}
+	cities     = []string{"new york", "san francisco", "seattle", "austin", "chicago", "boston", "denver", "miami"}
+	carriers   = []string{"ups", "fedex", "usps", "dhl"}
+)
+
+// pick returns a random word of words
+func pick(rng *rand.Rand, words []string) string {
+	return words[rng.Intn(len(words))]
+}
+
+// sentence returns n random words joined by space
+func sentence(rng *rand.Rand, n int) string {
+	words := make([]string, n)
+	for i := range words {
+		if i%2 == 0 {
+			words[i] = pick(rng, adjectives)
+		} else {
+			words[i] = pick(rng, nouns)
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,235 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+// Package health serves grpc.health.v1.Health on api server, status of a
+// service follows health of storage it depends on, probed in background
+package health
+
+import (
+	"context"
+	"errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Checker is a storage probed by health checks, eg: proxy.Client
+type Checker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// CheckerOf returns storage as Checker, nil if it can not be probed, eg:
+// memory storage, which is always healthy. storage failed to be created
+// is never healthy
+func CheckerOf(storage interface{}) Checker {
+	if storage == nil {
+		return missing{}
+	}
+	checker, _ := storage.(Checker)
+	return checker
+}
+
+// missing is checker of storage failed to be created
+type missing struct{}
+
+func (missing) HealthCheck(context.Context) error {
+	return errors.New("storage is not created")
+}
+
+// probe tracks results of a checker in a row, a healthy checker turns
+// unhealthy after HealthFailures failures, and back after HealthSuccesses
+type probe struct {
+	checker   Checker
+	failures  int
+	successes int
+	healthy   bool
+}
+
+// Monitor probes storage of services every HealthInterval, and reports
+// status of each service and of the whole server ("") by health service.
+// services are NOT_SERVING until their storage is probed healthy
+type Monitor struct {
+	config   cfg.HealthConfig
+	server   *grpchealth.Server
+	lock     sync.Mutex
+	probes   map[Checker]*probe
+	services map[string][]*probe
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// Create monitor, call Start once services are added
+func NewMonitor(config *cfg.HealthConfig) *Monitor {
+	return &Monitor{
+		config:   *config,
+		server:   grpchealth.NewServer(),
+		probes:   make(map[Checker]*probe),
+		services: make(map[string][]*probe),
+	}
+}
+
+// Add adds service depending on checkers, a checker shared by services is
+// probed once per round, nil checkers are skipped
+func (m *Monitor) Add(service string, checkers ...Checker) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	probes := make([]*probe, 0, len(checkers))
+	for _, checker := range checkers {
+		if checker == nil {
+			continue
+		}
+		p, ok := m.probes[checker]
+		if !ok {
+			p = &probe{checker: checker}
+			m.probes[checker] = p
+		}
+		probes = append(probes, p)
+	}
+	m.services[service] = probes
+	m.update()
+}
+
+// Register registers health service in grpc server
+func (m *Monitor) Register(svr *grpc.Server) {
+	healthpb.RegisterHealthServer(svr, m.server)
+}
+
+// Start probes storage in background until Close
+func (m *Monitor) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	interval := m.config.HealthInterval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			m.check(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Close stops probing and moves all services to NOT_SERVING
+func (m *Monitor) Close() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.wg.Wait()
+	m.server.Shutdown()
+}
+
+// Handler serves readiness over http, 200 if all services are serving,
+// 503 otherwise
+func (m *Monitor) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, err := m.server.Check(r.Context(), &healthpb.HealthCheckRequest{})
+		if err != nil || response.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("NOT_SERVING\n"))
+			return
+		}
+		_, _ = w.Write([]byte("SERVING\n"))
+	})
+}
+
+// check probes every checker concurrently, and updates status once all
+// of them return
+func (m *Monitor) check(ctx context.Context) {
+	m.lock.Lock()
+	probes := make([]*probe, 0, len(m.probes))
+	for _, p := range m.probes {
+		probes = append(probes, p)
+	}
+	m.lock.Unlock()
+
+	results := make([]error, len(probes))
+	var wg sync.WaitGroup
+	for i, p := range probes {
+		wg.Add(1)
+		go func(i int, checker Checker) {
+			defer wg.Done()
+			probeCtx := ctx
+			if m.config.HealthTimeout > 0 {
+				var cancel context.CancelFunc
+				probeCtx, cancel = context.WithTimeout(ctx, m.config.HealthTimeout)
+				defer cancel()
+			}
+			results[i] = checker.HealthCheck(probeCtx)
+		}(i, p.checker)
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for i, p := range probes {
+		m.record(p, results[i])
+	}
+	m.update()
+}
+
+// record counts result of a probe and turns checker healthy or unhealthy
+// once threshold is reached
+func (m *Monitor) record(p *probe, err error) {
+	if err != nil {
+		p.failures, p.successes = p.failures+1, 0
+		if p.healthy && p.failures >= m.config.HealthFailures {
+			p.healthy = false
+			log.Errorf("storage not healthy after %d failed probes: %s", p.failures, err)
+		}
+		return
+	}
+	p.failures, p.successes = 0, p.successes+1
+	if !p.healthy && p.successes >= m.config.HealthSuccesses {
+		p.healthy = true
+		log.Infof("storage healthy after %d probes", p.successes)
+	}
+}
+
+// update sets status of services, whole server is serving only if all
+// services are
+func (m *Monitor) update() {
+	all := healthpb.HealthCheckResponse_SERVING
+	for service, probes := range m.services {
+		status := healthpb.HealthCheckResponse_SERVING
+		for _, p := range probes {
+			if !p.healthy {
+				status = healthpb.HealthCheckResponse_NOT_SERVING
+			}
+		}
+		if status != healthpb.HealthCheckResponse_SERVING {
+			all = status
+		}
+		m.server.SetServingStatus(service, status)
+	}
+	m.server.SetServingStatus("", all)
+}
@@ -0,0 +1,125 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package health
+
+import (
+	"context"
+	"errors"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// checker fails while err is set
+type checker struct {
+	lock  sync.Mutex
+	err   error
+	calls int
+}
+
+func (c *checker) HealthCheck(ctx context.Context) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.calls++
+	return c.err
+}
+
+func (c *checker) fail(err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.err = err
+}
+
+// Test on status of services by probe thresholds
+func TestMonitor(t *testing.T) {
+	monitor := NewMonitor(&cfg.HealthConfig{HealthFailures: 2, HealthSuccesses: 2, HealthTimeout: time.Second})
+	sku, payment := &checker{}, &checker{}
+	monitor.Add("skupb.SkuService", sku)
+	monitor.Add("orderpb.OrderService", sku, payment)
+	monitor.Add("userpb.UserService", CheckerOf(struct{}{}))
+	monitor.Add("productpb.ProductService", CheckerOf(nil))
+
+	expect := func(round string, service string, expected healthpb.HealthCheckResponse_ServingStatus) {
+		response, err := monitor.server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil || response.GetStatus() != expected {
+			t.Errorf("%s: expect %s %s, got %v %v", round, service, expected, response.GetStatus(), err)
+		}
+	}
+	ctx := context.Background()
+
+	expect("before probe", "skupb.SkuService", healthpb.HealthCheckResponse_NOT_SERVING)
+	expect("before probe", "userpb.UserService", healthpb.HealthCheckResponse_SERVING)
+	monitor.check(ctx)
+	expect("first probe", "skupb.SkuService", healthpb.HealthCheckResponse_NOT_SERVING)
+	monitor.check(ctx)
+	expect("second probe", "skupb.SkuService", healthpb.HealthCheckResponse_SERVING)
+	expect("second probe", "orderpb.OrderService", healthpb.HealthCheckResponse_SERVING)
+	expect("second probe", "productpb.ProductService", healthpb.HealthCheckResponse_NOT_SERVING)
+	expect("second probe", "", healthpb.HealthCheckResponse_NOT_SERVING)
+	if sku.calls != 2 {
+		t.Errorf("expect shared checker probed once per round, got %d", sku.calls)
+	}
+
+	payment.fail(errors.New("proxy down"))
+	monitor.check(ctx)
+	expect("first failure", "orderpb.OrderService", healthpb.HealthCheckResponse_SERVING)
+	monitor.check(ctx)
+	expect("second failure", "orderpb.OrderService", healthpb.HealthCheckResponse_NOT_SERVING)
+	expect("second failure", "skupb.SkuService", healthpb.HealthCheckResponse_SERVING)
+
+	payment.fail(nil)
+	monitor.check(ctx)
+	monitor.check(ctx)
+	expect("recovered", "orderpb.OrderService", healthpb.HealthCheckResponse_SERVING)
+
+	monitor.Close()
+	expect("closed", "skupb.SkuService", healthpb.HealthCheckResponse_NOT_SERVING)
+}
+
+// Test on background probes and readiness handler
+func TestMonitorStart(t *testing.T) {
+	monitor := NewMonitor(&cfg.HealthConfig{HealthInterval: 10 * time.Millisecond, HealthFailures: 1, HealthSuccesses: 1})
+	sku := &checker{}
+	monitor.Add("skupb.SkuService", sku)
+
+	ready := func() int {
+		recorder := httptest.NewRecorder()
+		monitor.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/ready", nil))
+		return recorder.Code
+	}
+	if code := ready(); code != http.StatusServiceUnavailable {
+		t.Errorf("expect not ready before probe, got %d", code)
+	}
+	monitor.Start()
+	defer monitor.Close()
+
+	waitFor := func(expected int) {
+		for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+			if ready() == expected {
+				return
+			}
+		}
+		t.Errorf("expect readiness %d", expected)
+	}
+	waitFor(http.StatusOK)
+	sku.fail(errors.New("proxy down"))
+	waitFor(http.StatusServiceUnavailable)
+}
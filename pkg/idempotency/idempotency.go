@@ -0,0 +1,268 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/xidongc-wish/mgo/bson"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"github.com/xidongc/mongo_ebenchmark/pkg/proxy"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	protov2 "google.golang.org/protobuf/proto"
+	"time"
+)
+
+// MetadataKey is grpc metadata key api callers set to make a request
+// idempotent, eg: x-idempotency-key: 5b6f0c1e, retries with the same key
+// get the original response
+const MetadataKey = "x-idempotency-key"
+
+// collection idempotency keys are stored in
+const ns = "idempotency"
+
+// status of idempotency key
+const (
+	pending = "pending"
+	done    = "done"
+)
+
+// default time a pending key is held without renewal before its request
+// is considered lost
+const defaultLease = 30 * time.Second
+
+// attempts to store response of a request
+const storeAttempts = 3
+
+// Store keeps idempotency keys with responses of requests, a key is
+// claimed by the first request and bound to hash of the request, a key
+// reused by another request is rejected with InvalidArgument. concurrent
+// requests of a pending key are rejected with Aborted, and requests of a
+// done key replay its response. the request holding a key renews it while
+// it runs, a pending key not renewed within Lease is considered lost and
+// rejected with Unavailable, as the lost request may have succeeded
+type Store struct {
+	Storage proxy.Storage
+	Lease   time.Duration
+}
+
+type keyContext struct{}
+
+// WithKey returns a copy of ctx carrying idempotency key, used by callers
+// within the same process instead of grpc metadata, an empty key drops
+// key of incoming request, eg: for nested calls of another service
+func WithKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, keyContext{}, key)
+}
+
+// Key returns idempotency key of a request, from WithKey or incoming grpc
+// metadata, empty if the request has none
+func Key(ctx context.Context) string {
+	if key, ok := ctx.Value(keyContext{}).(string); ok {
+		return key
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(MetadataKey); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// Do runs fn of request once per scope and idempotency key of ctx,
+// response of fn is stored with key, and unmarshalled into replay when the
+// key is seen again. fn always runs if ctx has no key or store is nil, and
+// a failed fn releases the key so that the request can be retried
+func (s *Store) Do(ctx context.Context, scope string, request proto.Message, replay proto.Message, fn func() (proto.Message, error)) (proto.Message, error) {
+	key := Key(ctx)
+	if s == nil || key == "" {
+		return fn()
+	}
+
+	hash, err := requestHash(request)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s: hash request of key %s error: %s", ns, key, err)
+	}
+	owner := uuid.New().String()
+	record, err := s.claim(ctx, scope, key, owner, hash)
+	if err != nil {
+		return nil, err
+	}
+	if record["request"] != hash {
+		return nil, status.Errorf(codes.InvalidArgument, "idempotency key %s is reused by a different request", key)
+	}
+	if record["owner"] != owner {
+		return s.replay(key, record, replay)
+	}
+	return s.run(ctx, scope, key, owner, fn)
+}
+
+// claim inserts key of request hash in pending status owned by owner if
+// it does not exist, and returns record of key
+func (s *Store) claim(ctx context.Context, scope string, key string, owner string, hash string) (bson.M, error) {
+	now := time.Now().UnixNano()
+	param := &proxy.FindModifyParam{
+		Filter: bson.M{"scope": scope, "key": key},
+		Desired: bson.M{"$setOnInsert": bson.M{
+			"scope":   scope,
+			"key":     key,
+			"request": hash,
+			"owner":   owner,
+			"status":  pending,
+			"created": now,
+			"renewed": now,
+		}},
+		Mode: proxy.FindAndUpsert,
+	}
+	record, err := s.Storage.FindAndModify(ctx, param)
+	if err != nil {
+		log.Error(err)
+		return nil, proxy.ToStatus(err)
+	}
+	if record == nil {
+		return nil, status.Errorf(codes.Internal, "%s: claim key %s returned empty record", ns, key)
+	}
+	return record, nil
+}
+
+// replay returns response stored with a done key, a pending key is
+// rejected with Aborted while its request renews it, and with Unavailable
+// once the request is lost, as it may have succeeded
+func (s *Store) replay(key string, record bson.M, replay proto.Message) (proto.Message, error) {
+	if record["status"] == done {
+		response, ok := record["response"].([]byte)
+		if !ok {
+			return nil, status.Errorf(codes.Internal, "%s: key %s has no response", ns, key)
+		}
+		if err := proto.Unmarshal(response, replay); err != nil {
+			log.Error(err)
+			return nil, status.Errorf(codes.Internal, "%s: unmarshal response of key %s error: %s", ns, key, err)
+		}
+		return replay, nil
+	}
+
+	renewed, _ := record["renewed"].(int64)
+	if time.Since(time.Unix(0, renewed)) < s.lease() {
+		return nil, status.Errorf(codes.Aborted, "request with idempotency key %s is in progress", key)
+	}
+	return nil, status.Errorf(codes.Unavailable, "request with idempotency key %s was lost, its outcome is unknown", key)
+}
+
+// run runs fn holding key, renewing it until fn returned and its response
+// is stored, or releasing key on error
+func (s *Store) run(ctx context.Context, scope string, key string, owner string, fn func() (proto.Message, error)) (proto.Message, error) {
+	filter := bson.M{"scope": scope, "key": key, "owner": owner}
+	// renewal and store outlive the request, its response is kept even if
+	// the caller is gone
+	background, stop := context.WithCancel(context.Background())
+	defer stop()
+	go s.renew(background, scope, key, owner, s.lease()/3)
+
+	response, err := fn()
+	if err != nil {
+		if _, removeErr := s.Storage.Remove(ctx, &proxy.RemoveParam{Filter: filter}); removeErr != nil {
+			log.Errorf("%s: release key %s error: %s", ns, key, removeErr)
+		}
+		return nil, err
+	}
+
+	// response is returned even if it can not be stored, the key stays
+	// pending and is rejected once its lease expires
+	data, err := proto.Marshal(response)
+	if err != nil {
+		log.Errorf("%s: marshal response of key %s error: %s", ns, key, err)
+		return response, nil
+	}
+	param := &proxy.UpdateParam{
+		Filter: filter,
+		Update: bson.M{"$set": bson.M{"status": done, "response": data, "updated": time.Now().UnixNano()}},
+	}
+	for attempt := 1; attempt <= storeAttempts; attempt++ {
+		if _, err = s.Storage.Update(background, param); err == nil {
+			break
+		}
+		log.Errorf("%s: store response of key %s error (attempt %d): %s", ns, key, attempt, err)
+		time.Sleep(time.Duration(attempt) * s.lease() / 10)
+	}
+	return response, nil
+}
+
+// renew keeps pending key held by owner alive every interval until ctx
+// is done
+func (s *Store) renew(ctx context.Context, scope string, key string, owner string, interval time.Duration) {
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			param := &proxy.UpdateParam{
+				Filter: bson.M{"scope": scope, "key": key, "owner": owner, "status": pending},
+				Update: bson.M{"$set": bson.M{"renewed": time.Now().UnixNano()}},
+			}
+			if _, err := s.Storage.Update(ctx, param); err != nil && ctx.Err() == nil {
+				log.Errorf("%s: renew key %s error: %s", ns, key, err)
+			}
+		}
+	}
+}
+
+// requestHash returns hex sha256 of request in deterministic encoding
+func requestHash(request proto.Message) (string, error) {
+	data, err := protov2.MarshalOptions{Deterministic: true}.Marshal(proto.MessageV2(request))
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// lease returns Lease, or default lease if it is not set
+func (s *Store) lease() time.Duration {
+	if s.Lease > 0 {
+		return s.Lease
+	}
+	return defaultLease
+}
+
+// Create idempotency Store on idempotency collection
+func NewStore(config *cfg.ProxyConfig, cancel context.CancelFunc) (*Store, error) {
+	storage, err := proxy.NewStorage(config, ns, cancel)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{Storage: storage}, nil
+}
+
+// Create idempotency Store on connections shared by manager
+func NewSharedStore(manager *proxy.Manager) (*Store, error) {
+	storage, err := manager.Storage(ns)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{Storage: storage}, nil
+}
@@ -0,0 +1,156 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/protobuf/proto"
+	"github.com/xidongc-wish/mgo/bson"
+	"github.com/xidongc/mongo_ebenchmark/pkg/proxy"
+	"github.com/xidongc/mongo_ebenchmark/pkg/proxy/proxytest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStoreDo(t *testing.T) {
+	server, ctx, cancel := proxytest.Start(t)
+
+	store, err := NewStore(server.Config(), cancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Storage.Close()
+
+	var calls int32
+	request := wrapperspb.String("request")
+	do := func(ctx context.Context, scope string, fail bool) (string, error) {
+		response, err := store.Do(ctx, scope, request, &wrapperspb.StringValue{}, func() (proto.Message, error) {
+			atomic.AddInt32(&calls, 1)
+			if fail {
+				return nil, errors.New("failed")
+			}
+			return wrapperspb.String(time.Now().String()), nil
+		})
+		if err != nil {
+			return "", err
+		}
+		return response.(*wrapperspb.StringValue).GetValue(), nil
+	}
+
+	keyed := metadata.NewIncomingContext(ctx, metadata.Pairs(MetadataKey, "k1"))
+	first, err := do(keyed, "charge", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := do(keyed, "charge", false)
+	if err != nil || replayed != first || calls != 1 {
+		t.Errorf("expect response replayed, got %s, %v after %d calls", replayed, err, calls)
+	}
+	if _, err = do(keyed, "order", false); err != nil || calls != 2 {
+		t.Errorf("expect key scoped, got %v after %d calls", err, calls)
+	}
+	if _, err = do(WithKey(keyed, ""), "charge", false); err != nil || calls != 3 {
+		t.Errorf("expect key dropped, got %v after %d calls", err, calls)
+	}
+	if _, err = do(ctx, "charge", false); err != nil || calls != 4 {
+		t.Errorf("expect request without key run, got %v after %d calls", err, calls)
+	}
+
+	// failed request releases key
+	failed := WithKey(ctx, "k2")
+	if _, err = do(failed, "charge", true); err == nil {
+		t.Error("expect error")
+	}
+	if _, err = do(failed, "charge", false); err != nil || calls != 6 {
+		t.Errorf("expect retry of failed request run, got %v after %d calls", err, calls)
+	}
+
+	// key reused by a different request is rejected
+	request = wrapperspb.String("another request")
+	if _, err = do(keyed, "charge", false); status.Code(err) != codes.InvalidArgument || calls != 6 {
+		t.Errorf("expect reused key rejected, got %v after %d calls", err, calls)
+	}
+	request = wrapperspb.String("request")
+
+	// pending key of a lost request is rejected, and never run again once
+	// its lease expired, as the lost request may have succeeded
+	pending := WithKey(ctx, "k3")
+	hash, err := requestHash(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = store.claim(ctx, "charge", "k3", "lost", hash); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = do(pending, "charge", false); status.Code(err) != codes.Aborted {
+		t.Errorf("expect pending key rejected, got %v", err)
+	}
+	store.Lease = time.Nanosecond
+	if _, err = do(pending, "charge", false); status.Code(err) != codes.Unavailable || calls != 6 {
+		t.Errorf("expect expired key rejected, got %v after %d calls", err, calls)
+	}
+
+	records, err := store.Storage.Find(ctx, &proxy.QueryParam{Filter: bson.M{"status": done}})
+	if err != nil || len(records) != 3 {
+		t.Errorf("expect 3 done keys, got %d, %v", len(records), err)
+	}
+}
+
+// Test on key renewed while a request runs longer than lease
+func TestStoreRenew(t *testing.T) {
+	server, ctx, cancel := proxytest.Start(t)
+
+	store, err := NewStore(server.Config(), cancel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Storage.Close()
+	store.Lease = 30 * time.Millisecond
+
+	keyed := WithKey(ctx, "slow")
+	request := wrapperspb.String("request")
+	var calls int32
+	fn := func() (proto.Message, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			time.Sleep(5 * store.Lease)
+		}
+		return wrapperspb.String("response"), nil
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := store.Do(keyed, "charge", request, &wrapperspb.StringValue{}, fn)
+		done <- err
+	}()
+	time.Sleep(3 * store.Lease)
+	if _, err = store.Do(keyed, "charge", request, &wrapperspb.StringValue{}, fn); status.Code(err) != codes.Aborted {
+		t.Errorf("expect renewed key in progress, got %v", err)
+	}
+	if err = <-done; err != nil {
+		t.Fatal(err)
+	}
+	response, err := store.Do(keyed, "charge", request, &wrapperspb.StringValue{}, fn)
+	if err != nil || response.(*wrapperspb.StringValue).GetValue() != "response" || atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("expect response replayed, got %v, %v after %d calls", response, err, calls)
+	}
+}
@@ -0,0 +1,109 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package metrics
+
+import (
+	"fmt"
+	"github.com/bojand/ghz/runner"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/expfmt"
+	"io"
+)
+
+// ampMetrics are amplification run summaries, labelled by proxy method
+// and namespace, gauges keep summary of the latest run
+type ampMetrics struct {
+	runs     *prometheus.CounterVec
+	rps      *prometheus.GaugeVec
+	latency  *prometheus.GaugeVec
+	requests *prometheus.GaugeVec
+	duration *prometheus.GaugeVec
+}
+
+// amp metrics exported on /metrics
+var amp = newAmpMetrics(factory)
+
+// newAmpMetrics registers amplification metrics by factory
+func newAmpMetrics(factory promauto.Factory) *ampMetrics {
+	labels := []string{"method", "namespace"}
+	return &ampMetrics{
+		runs: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "ebenchmark_amp_runs_total",
+			Help: "amplification runs, failed runs are counted by result error",
+		}, append(labels, "result")),
+		rps: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "ebenchmark_amp_rps",
+			Help: "requests per second of latest amplification run",
+		}, labels),
+		latency: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "ebenchmark_amp_latency_seconds",
+			Help: "latency of latest amplification run, by quantile or average",
+		}, append(labels, "quantile")),
+		requests: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "ebenchmark_amp_requests",
+			Help: "requests of latest amplification run, by status code",
+		}, append(labels, "code")),
+		duration: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "ebenchmark_amp_duration_seconds",
+			Help: "duration of latest amplification run",
+		}, labels),
+	}
+}
+
+// observe records summary of an amplification run, report is nil if
+// the run failed
+func (m *ampMetrics) observe(method string, namespace string, report *runner.Report, err error) {
+	if err != nil || report == nil {
+		m.runs.WithLabelValues(method, namespace, "error").Inc()
+		return
+	}
+	m.runs.WithLabelValues(method, namespace, "ok").Inc()
+	m.rps.WithLabelValues(method, namespace).Set(report.Rps)
+	m.duration.WithLabelValues(method, namespace).Set(report.Total.Seconds())
+	m.latency.WithLabelValues(method, namespace, "avg").Set(report.Average.Seconds())
+	for _, l := range report.LatencyDistribution {
+		m.latency.WithLabelValues(method, namespace, fmt.Sprintf("%g", float64(l.Percentage)/100)).Set(l.Latency.Seconds())
+	}
+	for code, count := range report.StatusCodeDist {
+		m.requests.WithLabelValues(method, namespace, code).Set(float64(count))
+	}
+}
+
+// ObserveAmp exports summary of an amplification run on /metrics, report
+// is nil if the run failed
+func ObserveAmp(method string, namespace string, report *runner.Report, err error) {
+	amp.observe(method, namespace, report, err)
+}
+
+// WriteAmp writes summary of an amplification run in text exposition
+// format, with the same metrics ObserveAmp exports on /metrics
+func WriteAmp(w io.Writer, method string, namespace string, report *runner.Report) error {
+	registry := prometheus.NewRegistry()
+	newAmpMetrics(promauto.With(registry)).observe(method, namespace, report, nil)
+	families, err := registry.Gather()
+	if err != nil {
+		return err
+	}
+	encoder := expfmt.NewEncoder(w, expfmt.FmtText)
+	for _, family := range families {
+		if err = encoder.Encode(family); err != nil {
+			return err
+		}
+	}
+	return nil
+}
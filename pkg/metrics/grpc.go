@@ -0,0 +1,85 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package metrics
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"strings"
+	"time"
+)
+
+// grpc server metrics, labelled by service and method of api server
+var (
+	serverLatency = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ebenchmark_grpc_server_handling_seconds",
+		Help:    "latency of rpcs handled by api server",
+		Buckets: DefaultBuckets,
+	}, []string{"service", "method"})
+	serverHandled = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "ebenchmark_grpc_server_handled_total",
+		Help: "rpcs handled by api server, by grpc code",
+	}, []string{"service", "method", "code"})
+	serverInFlight = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ebenchmark_grpc_server_in_flight",
+		Help: "rpcs in flight on api server",
+	}, []string{"service", "method"})
+)
+
+// UnaryServerInterceptor records latency, result code and in flight
+// requests of unary rpcs
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		done := serverCall(info.FullMethod)
+		defer func() { done(err) }()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor records latency, result code and in flight
+// streams of streaming rpcs
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		done := serverCall(info.FullMethod)
+		defer func() { done(err) }()
+		return handler(srv, ss)
+	}
+}
+
+// serverCall records a rpc in flight, done records its latency and code
+func serverCall(fullMethod string) (done func(err error)) {
+	service, method := splitMethod(fullMethod)
+	inFlight := serverInFlight.WithLabelValues(service, method)
+	inFlight.Inc()
+	start := time.Now()
+	return func(err error) {
+		inFlight.Dec()
+		serverLatency.WithLabelValues(service, method).Observe(time.Since(start).Seconds())
+		serverHandled.WithLabelValues(service, method, status.Code(err).String()).Inc()
+	}
+}
+
+// splitMethod splits full method name, eg: /orderpb.OrderService/New
+func splitMethod(fullMethod string) (service string, method string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "unknown", fullMethod
+}
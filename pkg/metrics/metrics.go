@@ -14,4 +14,35 @@
  *
  */
 
+// Package metrics keeps counters, gauges and histograms of services,
+// proxy calls and amplification runs in a prometheus registry, exposed
+// on /metrics, so long soak tests can be scraped and graphed while they
+// run
+//
+// Relevant documentation:
+//
+//     https://prometheus.io/docs/instrumenting/writing_exporters/
+//
 package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+)
+
+// DefaultBuckets of latency histograms in seconds, proxy calls of a
+// nearby proxy take less than a millisecond
+var DefaultBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Default registry metrics of this package are registered in
+var Default = prometheus.NewRegistry()
+
+// factory registers metrics in Default
+var factory = promauto.With(Default)
+
+// Handler serves Default registry
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Default, promhttp.HandlerOpts{})
+}
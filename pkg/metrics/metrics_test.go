@@ -0,0 +1,107 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"github.com/bojand/ghz/runner"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test on amplification summary written in text exposition format
+func TestWriteAmp(t *testing.T) {
+	report := &runner.Report{
+		Total:          2 * time.Second,
+		Average:        time.Millisecond,
+		Rps:            1000,
+		StatusCodeDist: map[string]int{"OK": 1999, "Unavailable": 1},
+		LatencyDistribution: []runner.LatencyDistribution{
+			{Percentage: 99, Latency: 5 * time.Millisecond},
+		},
+	}
+	var b strings.Builder
+	if err := WriteAmp(&b, "mprpc.MongoProxy.Find", "sku", report); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"# TYPE ebenchmark_amp_rps gauge",
+		`ebenchmark_amp_rps{method="mprpc.MongoProxy.Find",namespace="sku"} 1000`,
+		`ebenchmark_amp_latency_seconds{method="mprpc.MongoProxy.Find",namespace="sku",quantile="0.99"} 0.005`,
+		`ebenchmark_amp_requests{code="Unavailable",method="mprpc.MongoProxy.Find",namespace="sku"} 1`,
+		`ebenchmark_amp_runs_total{method="mprpc.MongoProxy.Find",namespace="sku",result="ok"} 1`,
+	} {
+		if !strings.Contains(b.String(), line+"\n") {
+			t.Errorf("expect %s in:\n%s", line, b.String())
+		}
+	}
+}
+
+// sampleCount of a histogram series
+func sampleCount(t *testing.T, observer prometheus.Observer) uint64 {
+	m := &dto.Metric{}
+	if err := observer.(prometheus.Metric).Write(m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
+
+// Test on metrics recorded by server interceptor and proxy calls
+func TestInterceptorAndProxyCall(t *testing.T) {
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/orderpb.OrderService/Pay"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		if testutil.ToFloat64(serverInFlight.WithLabelValues("orderpb.OrderService", "Pay")) != 1 {
+			t.Error("expect rpc in flight")
+		}
+		return nil, status.Error(codes.FailedPrecondition, "paid")
+	}
+	if _, err := interceptor(context.Background(), nil, info, handler); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expect handler error returned, got %v", err)
+	}
+	if testutil.ToFloat64(serverHandled.WithLabelValues("orderpb.OrderService", "Pay", "FailedPrecondition")) != 1 ||
+		sampleCount(t, serverLatency.WithLabelValues("orderpb.OrderService", "Pay")) != 1 ||
+		testutil.ToFloat64(serverInFlight.WithLabelValues("orderpb.OrderService", "Pay")) != 0 {
+		t.Error("expect failed rpc recorded")
+	}
+
+	done := ProxyCall("mprpc.MongoProxy.Find", "sku", "turbo")
+	if testutil.ToFloat64(proxyInFlight.WithLabelValues("mprpc.MongoProxy.Find", "sku", "turbo")) != 1 {
+		t.Error("expect proxy call in flight")
+	}
+	done(status.Code(errors.New("timeout")))
+	if testutil.ToFloat64(proxyErrors.WithLabelValues("mprpc.MongoProxy.Find", "sku", "turbo", "Unknown")) != 1 ||
+		sampleCount(t, proxyLatency.WithLabelValues("mprpc.MongoProxy.Find", "sku", "turbo")) != 1 ||
+		testutil.ToFloat64(proxyInFlight.WithLabelValues("mprpc.MongoProxy.Find", "sku", "turbo")) != 0 {
+		t.Error("expect failed proxy call recorded")
+	}
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	if !strings.Contains(recorder.Body.String(), `ebenchmark_grpc_server_handled_total{code="FailedPrecondition",method="Pay",service="orderpb.OrderService"} 1`) {
+		t.Errorf("expect handled rpc exported, got:\n%s", recorder.Body.String())
+	}
+}
@@ -0,0 +1,72 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"time"
+)
+
+// proxy call metrics, labelled by proxy method, namespace and consistency
+// profile the call runs with
+var (
+	proxyLatency = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ebenchmark_proxy_request_duration_seconds",
+		Help:    "latency of proxy calls",
+		Buckets: DefaultBuckets,
+	}, []string{"method", "namespace", "consistency"})
+	proxyErrors = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "ebenchmark_proxy_errors_total",
+		Help: "proxy calls failed, by grpc code",
+	}, []string{"method", "namespace", "consistency", "code"})
+	proxyInFlight = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ebenchmark_proxy_in_flight",
+		Help: "proxy calls in flight",
+	}, []string{"method", "namespace", "consistency"})
+	poolInFlight = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ebenchmark_proxy_pool_in_flight",
+		Help: "rpcs in flight on a pooled proxy connection",
+	}, []string{"conn"})
+)
+
+// ProxyCall records a proxy call in flight, done records its latency and
+// error code once it returns, eg:
+//
+//     done := metrics.ProxyCall(Find, "sku", "turbo")
+//     defer func() { done(status.Code(err)) }()
+//
+func ProxyCall(method string, namespace string, consistency string) (done func(code codes.Code)) {
+	inFlight := proxyInFlight.WithLabelValues(method, namespace, consistency)
+	inFlight.Inc()
+	start := time.Now()
+	return func(code codes.Code) {
+		inFlight.Dec()
+		proxyLatency.WithLabelValues(method, namespace, consistency).Observe(time.Since(start).Seconds())
+		if code != codes.OK {
+			proxyErrors.WithLabelValues(method, namespace, consistency, code.String()).Inc()
+		}
+	}
+}
+
+// PoolCall records a rpc in flight on pooled connection conn, done is
+// called once rpc or stream finished
+func PoolCall(conn string) (done func()) {
+	inFlight := poolInFlight.WithLabelValues(conn)
+	inFlight.Inc()
+	return inFlight.Dec
+}
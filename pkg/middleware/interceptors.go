@@ -0,0 +1,290 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package middleware
+
+import (
+	"context"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// RequestIdKey is grpc metadata key of request id, id sent by caller is
+// kept, otherwise one is generated, either is sent back in response header
+const RequestIdKey = "x-request-id"
+
+type requestIdKey struct{}
+
+// RequestIdFromContext returns request id of rpc, empty if request-id
+// middleware is disabled
+func RequestIdFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIdKey{}).(string)
+	return id
+}
+
+// withRequestId returns a copy of ctx with request id of rpc
+func withRequestId(ctx context.Context) (context.Context, string) {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(RequestIdKey); len(values) > 0 {
+			id = values[0]
+		}
+	}
+	if id == "" {
+		id = uuid.New().String()
+	}
+	return context.WithValue(ctx, requestIdKey{}, id), id
+}
+
+func unaryRequestId(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, id := withRequestId(ctx)
+	// fails only if there is no grpc transport, eg: in tests
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIdKey, id))
+	return handler(ctx, req)
+}
+
+func streamRequestId(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, id := withRequestId(ss.Context())
+	_ = ss.SetHeader(metadata.Pairs(RequestIdKey, id))
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+func unaryAccessLog(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	start := time.Now()
+	defer func() { accessLog(ctx, info.FullMethod, start, err) }()
+	return handler(ctx, req)
+}
+
+func streamAccessLog(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	start := time.Now()
+	defer func() { accessLog(ss.Context(), info.FullMethod, start, err) }()
+	return handler(srv, ss)
+}
+
+// accessLog logs a rpc with request id, peer, code and latency, failed
+// rpcs are logged as warning
+func accessLog(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	fields := log.Fields{
+		"method":     method,
+		"code":       code.String(),
+		"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if id := RequestIdFromContext(ctx); id != "" {
+		fields["request_id"] = id
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		fields["peer"] = p.Addr.String()
+	}
+	entry := log.WithFields(fields)
+	if err != nil {
+		entry.WithField("error", status.Convert(err).Message()).Warn("rpc failed")
+		return
+	}
+	entry.Info("rpc handled")
+}
+
+func unaryRecovery(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer recoverRpc(ctx, info.FullMethod, &err)
+	return handler(ctx, req)
+}
+
+func streamRecovery(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer recoverRpc(ss.Context(), info.FullMethod, &err)
+	return handler(srv, ss)
+}
+
+// recoverRpc converts panic of a rpc into Internal error, so a bug in one
+// handler does not kill a long running benchmark
+func recoverRpc(ctx context.Context, method string, err *error) {
+	if r := recover(); r != nil {
+		log.WithFields(log.Fields{
+			"method":     method,
+			"request_id": RequestIdFromContext(ctx),
+		}).Errorf("rpc panic: %v\n%s", r, debug.Stack())
+		*err = status.Errorf(codes.Internal, "panic: %v", r)
+	}
+}
+
+// limiter limits rpcs handled concurrently, a rpc waits up to wait for
+// a slot, or is rejected at once if wait is 0
+type limiter struct {
+	slots chan struct{}
+	wait  time.Duration
+}
+
+func newLimiter(max int, wait time.Duration) *limiter {
+	if max <= 0 {
+		return &limiter{}
+	}
+	return &limiter{slots: make(chan struct{}, max), wait: wait}
+}
+
+func (l *limiter) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := l.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer l.release()
+	return handler(ctx, req)
+}
+
+func (l *limiter) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := l.acquire(ss.Context()); err != nil {
+		return err
+	}
+	defer l.release()
+	return handler(srv, ss)
+}
+
+func (l *limiter) acquire(ctx context.Context) error {
+	if l.slots == nil {
+		return nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+	if l.wait > 0 {
+		timer := time.NewTimer(l.wait)
+		defer timer.Stop()
+		select {
+		case l.slots <- struct{}{}:
+			return nil
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return status.Error(codes.DeadlineExceeded, ctx.Err().Error())
+			}
+			return status.Error(codes.Canceled, ctx.Err().Error())
+		case <-timer.C:
+		}
+	}
+	return status.Errorf(codes.ResourceExhausted, "%d rpcs in flight, try later", cap(l.slots))
+}
+
+func (l *limiter) release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// defaultTimeouts are deadlines of methods calling proxy several times,
+// in multiples of rpc timeout
+var defaultTimeouts = map[string]float64{
+	"orderpb.OrderService/New":              3,
+	"orderpb.OrderService/Pay":              3,
+	"orderpb.OrderService/Cancel":           2,
+	"orderpb.OrderService/Fulfil":           2,
+	"orderpb.OrderService/Return":           3,
+	"productpb.ProductService/New":          2,
+	"productpb.ProductService/Get":          2,
+	"productpb.ProductService/Update":       3,
+	"productpb.ProductService/Delete":       3,
+	"paymentpb.PaymentService/NewCharge":    2,
+	"paymentpb.PaymentService/RefundCharge": 2,
+}
+
+// deadlines sets deadline of a rpc to rpc timeout times factor of method,
+// a deadline sent by caller is kept if it is earlier
+type deadlines struct {
+	timeout time.Duration
+	factors map[string]float64
+}
+
+func newDeadlines(rpcTimeout time.Duration, methodTimeouts map[string]float64) *deadlines {
+	factors := make(map[string]float64, len(defaultTimeouts)+len(methodTimeouts))
+	for method, factor := range defaultTimeouts {
+		factors[method] = factor
+	}
+	for method, factor := range methodTimeouts {
+		factors[strings.TrimPrefix(method, "/")] = factor
+	}
+	return &deadlines{timeout: rpcTimeout, factors: factors}
+}
+
+// forMethod returns deadline of method, 0 if rpc timeout is not set
+func (d *deadlines) forMethod(fullMethod string) time.Duration {
+	factor, ok := d.factors[strings.TrimPrefix(fullMethod, "/")]
+	if !ok {
+		factor = 1
+	}
+	return time.Duration(float64(d.timeout) * factor)
+}
+
+func (d *deadlines) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if timeout := d.forMethod(info.FullMethod); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return handler(ctx, req)
+}
+
+func (d *deadlines) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if timeout := d.forMethod(info.FullMethod); timeout > 0 {
+		ctx, cancel := context.WithTimeout(ss.Context(), timeout)
+		defer cancel()
+		ss = &serverStream{ServerStream: ss, ctx: ctx}
+	}
+	return handler(srv, ss)
+}
+
+// validator is implemented by requests checking their own fields, see
+// validate.go of model pb packages
+type validator interface {
+	Validate() error
+}
+
+// validate rejects invalid request with InvalidArgument
+func validate(req interface{}) error {
+	if v, ok := req.(validator); ok {
+		if err := v.Validate(); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	return nil
+}
+
+func unaryValidate(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func streamValidate(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &validatingStream{ss})
+}
+
+// validatingStream validates every message received
+type validatingStream struct {
+	grpc.ServerStream
+}
+
+func (s *validatingStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return validate(m)
+}
@@ -0,0 +1,128 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+// Package middleware builds interceptor chain of api server, stages are
+// enabled and ordered by cfg.MiddlewareConfig, eg:
+//
+//     chain, err := middleware.New(&config.MiddlewareConfig, rpcTimeout)
+//     svr := grpc.NewServer(append(serverOpts, chain.ServerOptions()...)...)
+//
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"github.com/xidongc/mongo_ebenchmark/pkg/metrics"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"time"
+)
+
+// Middleware stages
+const (
+	Recovery  = "recovery"
+	RequestId = "request-id"
+	Tracing   = "tracing"
+	Metrics   = "metrics"
+	AccessLog = "access-log"
+	Limit     = "limit"
+	Deadline  = "deadline"
+	Validate  = "validate"
+)
+
+// stage is a middleware with its unary and stream interceptors
+type stage struct {
+	unary  grpc.UnaryServerInterceptor
+	stream grpc.StreamServerInterceptor
+}
+
+// stages creates middleware stages by name
+var stages = map[string]func(config *cfg.MiddlewareConfig, rpcTimeout time.Duration) stage{
+	Recovery: func(*cfg.MiddlewareConfig, time.Duration) stage {
+		return stage{unaryRecovery, streamRecovery}
+	},
+	RequestId: func(*cfg.MiddlewareConfig, time.Duration) stage {
+		return stage{unaryRequestId, streamRequestId}
+	},
+	Tracing: func(*cfg.MiddlewareConfig, time.Duration) stage {
+		return stage{otelgrpc.UnaryServerInterceptor(), otelgrpc.StreamServerInterceptor()}
+	},
+	Metrics: func(*cfg.MiddlewareConfig, time.Duration) stage {
+		return stage{metrics.UnaryServerInterceptor(), metrics.StreamServerInterceptor()}
+	},
+	AccessLog: func(*cfg.MiddlewareConfig, time.Duration) stage {
+		return stage{unaryAccessLog, streamAccessLog}
+	},
+	Limit: func(config *cfg.MiddlewareConfig, _ time.Duration) stage {
+		limiter := newLimiter(config.MaxConcurrent, config.LimitWait)
+		return stage{limiter.unary, limiter.stream}
+	},
+	Deadline: func(config *cfg.MiddlewareConfig, rpcTimeout time.Duration) stage {
+		deadlines := newDeadlines(rpcTimeout, config.MethodTimeouts)
+		return stage{deadlines.unary, deadlines.stream}
+	},
+	Validate: func(*cfg.MiddlewareConfig, time.Duration) stage {
+		return stage{unaryValidate, streamValidate}
+	},
+}
+
+// Chain of api server interceptors, the first one is outermost
+type Chain struct {
+	Names  []string
+	Unary  []grpc.UnaryServerInterceptor
+	Stream []grpc.StreamServerInterceptor
+}
+
+// Create interceptor chain of stages in config, per method deadlines are
+// derived from rpcTimeout of storage requests
+func New(config *cfg.MiddlewareConfig, rpcTimeout time.Duration) (*Chain, error) {
+	chain := &Chain{}
+	seen := make(map[string]bool)
+	for _, name := range config.Middlewares {
+		create, ok := stages[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown middleware %s", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("middleware %s is specified more than once", name)
+		}
+		seen[name] = true
+		s := create(config, rpcTimeout)
+		chain.Names = append(chain.Names, name)
+		chain.Unary = append(chain.Unary, s.unary)
+		chain.Stream = append(chain.Stream, s.stream)
+	}
+	return chain, nil
+}
+
+// ServerOptions installs chain in grpc server
+func (c *Chain) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(c.Unary...),
+		grpc.ChainStreamInterceptor(c.Stream...),
+	}
+}
+
+// serverStream overrides context of stream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
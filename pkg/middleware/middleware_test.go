@@ -0,0 +1,168 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package middleware
+
+import (
+	"context"
+	"errors"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+// request implements validator
+type request struct {
+	id string
+}
+
+func (r *request) Validate() error {
+	if r.id == "" {
+		return errors.New("id is required")
+	}
+	return nil
+}
+
+// chainUnary runs interceptors in order as grpc server does
+func chainUnary(interceptors []grpc.UnaryServerInterceptor, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) grpc.UnaryHandler {
+	if len(interceptors) == 0 {
+		return handler
+	}
+	next := chainUnary(interceptors[1:], info, handler)
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		return interceptors[0](ctx, req, info, next)
+	}
+}
+
+// Test on building chain from config
+func TestNew(t *testing.T) {
+	if _, err := New(&cfg.MiddlewareConfig{Middlewares: []string{RequestId, "unknown"}}, 0); err == nil {
+		t.Error("expect unknown middleware rejected")
+	}
+	if _, err := New(&cfg.MiddlewareConfig{Middlewares: []string{Recovery, AccessLog, Recovery}}, 0); err == nil {
+		t.Error("expect duplicate middleware rejected")
+	}
+	chain, err := New(&cfg.MiddlewareConfig{Middlewares: []string{Validate, Tracing, Metrics}}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain.Unary) != 3 || len(chain.Stream) != 3 || chain.Names[0] != Validate || len(chain.ServerOptions()) != 2 {
+		t.Errorf("unexpected chain %v", chain.Names)
+	}
+}
+
+// Test on request id, recovery, deadline and validation stages
+func TestChain(t *testing.T) {
+	config := &cfg.MiddlewareConfig{
+		Middlewares:    []string{Recovery, RequestId, AccessLog, Limit, Deadline, Validate},
+		MethodTimeouts: map[string]float64{"/orderpb.OrderService/Pay": 0.5},
+		MaxConcurrent:  10,
+	}
+	chain, err := New(config, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := func(ctx context.Context, method string, req interface{}, handler grpc.UnaryHandler) (interface{}, error) {
+		return chainUnary(chain.Unary, &grpc.UnaryServerInfo{FullMethod: method}, handler)(ctx, req)
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIdKey, "request-1"))
+	resp, err := run(ctx, "/productpb.ProductService/Get", &request{id: "1"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		if id := RequestIdFromContext(ctx); id != "request-1" {
+			t.Errorf("expect request id of caller, got %s", id)
+		}
+		if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) <= time.Second || time.Until(deadline) > 2*time.Second {
+			t.Errorf("expect deadline in 2s, got %v", deadline)
+		}
+		return "ok", nil
+	})
+	if err != nil || resp != "ok" {
+		t.Errorf("unexpected response %v, %v", resp, err)
+	}
+
+	_, err = run(context.Background(), "/orderpb.OrderService/Pay", &request{id: "1"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		if RequestIdFromContext(ctx) == "" {
+			t.Error("expect request id generated")
+		}
+		if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > 500*time.Millisecond {
+			t.Errorf("expect deadline in 500ms, got %v", deadline)
+		}
+		var items []int
+		return items[1], nil
+	})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("expect panic recovered as Internal, got %v", err)
+	}
+
+	_, err = run(context.Background(), "/orderpb.OrderService/Get", &request{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		t.Error("expect invalid request not handled")
+		return nil, nil
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expect InvalidArgument, got %v", err)
+	}
+
+	if timeout := newDeadlines(time.Second, nil).forMethod("/orderpb.OrderService/Fulfil"); timeout != 2*time.Second {
+		t.Errorf("expect fulfil deadline in 2s, got %v", timeout)
+	}
+}
+
+// Test on concurrency limit
+func TestLimiter(t *testing.T) {
+	limiter := newLimiter(1, 0)
+	info := &grpc.UnaryServerInfo{FullMethod: "/skupb.SkuService/Get"}
+	running, finish := make(chan struct{}), make(chan struct{})
+	done := make(chan error)
+	go func() {
+		_, err := limiter.unary(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			close(running)
+			<-finish
+			return nil, nil
+		})
+		done <- err
+	}()
+	<-running
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+	if _, err := limiter.unary(context.Background(), nil, info, handler); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expect ResourceExhausted, got %v", err)
+	}
+
+	limiter.wait = time.Minute
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.unary(ctx, nil, info, handler); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("expect DeadlineExceeded while waiting, got %v", err)
+	}
+
+	time.AfterFunc(10*time.Millisecond, func() { close(finish) })
+	if _, err := limiter.unary(context.Background(), nil, info, handler); err != nil {
+		t.Errorf("expect rpc handled once slot is released, got %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+
+	if _, err := newLimiter(0, 0).unary(context.Background(), nil, info, handler); err != nil {
+		t.Errorf("expect no limit, got %v", err)
+	}
+}
@@ -0,0 +1,326 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package proxy
+
+import (
+	"context"
+	"github.com/bojand/ghz/runner"
+	log "github.com/sirupsen/logrus"
+	"github.com/xidongc-wish/mgo/bson"
+	"github.com/xidongc/mongo_ebenchmark/mprpc"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"github.com/xidongc/mongo_ebenchmark/pkg/metrics"
+	"github.com/xidongc/mongo_ebenchmark/pkg/report"
+	"sync"
+	"time"
+)
+
+// amplifyJob is one ghz run queued by a real request of client, done
+// is called in background once run finished, report is nil if run failed
+type amplifyJob struct {
+	client  *Client
+	call    string
+	request interface{}
+	amp     cfg.Amplifier
+	done    func(report *runner.Report, err error)
+}
+
+// key of job, one job per rpc method and namespace can be pending
+func (job *amplifyJob) key() string {
+	return job.client.Collection.GetCollection() + "/" + job.call
+}
+
+// amplifier runs amplify jobs in background so real request path is
+// never blocked by ghz, it is shared by all Clients of a Pool
+//
+// jobs are queued in a bounded channel, new jobs are dropped when queue
+// is full or a job of the same rpc method and namespace is already
+// pending, jobs of a client are cancelled once client is closed, all
+// jobs once pool is closed
+type amplifier struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	jobs    chan *amplifyJob
+	wg      sync.WaitGroup // tracks queued and running jobs of all clients
+	lock    sync.Mutex
+	pending map[string]bool
+	closed  bool
+}
+
+// newAmplifier starts workers of amplifier
+func newAmplifier(queueSize int, workers int) *amplifier {
+	if queueSize <= 0 {
+		queueSize = 16
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	amp := &amplifier{
+		ctx:     ctx,
+		cancel:  cancel,
+		jobs:    make(chan *amplifyJob, queueSize),
+		pending: make(map[string]bool),
+	}
+	for i := 0; i < workers; i++ {
+		go amp.work()
+	}
+	return amp
+}
+
+// submit queues job without blocking, returns false if job is dropped
+func (a *amplifier) submit(job *amplifyJob) bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if a.closed || job.client.ampCtx.Err() != nil {
+		log.Warningf("%s: amplifier closed, drop amp job", job.call)
+		return false
+	}
+	key := job.key()
+	if a.pending[key] {
+		log.Debugf("%s: amp job already pending, skip", job.call)
+		return false
+	}
+	a.wg.Add(1)
+	job.client.amplifierWG.Add(1)
+	select {
+	case a.jobs <- job:
+		a.pending[key] = true
+		return true
+	default:
+		job.client.amplifierWG.Done()
+		a.wg.Done()
+		log.Warningf("%s: amp queue full, drop amp job", job.call)
+		return false
+	}
+}
+
+// work runs queued jobs until queue is closed, jobs left in queue
+// after ctx of their client cancelled are skipped
+func (a *amplifier) work() {
+	for job := range a.jobs {
+		client := job.client
+		var rep *runner.Report
+		err := client.ampCtx.Err()
+		if err == nil {
+			rep, err = client.run(client.ampCtx, job.call, job.request, job.amp)
+			metrics.ObserveAmp(job.call, client.Collection.GetCollection(), rep, err)
+		}
+		if err != nil {
+			log.Errorf("%s: amplify error with: %s", job.call, err)
+		}
+		if job.done != nil {
+			job.done(rep, err)
+		}
+
+		a.lock.Lock()
+		delete(a.pending, job.key())
+		a.lock.Unlock()
+		client.amplifierWG.Done()
+		a.wg.Done()
+	}
+}
+
+// detach stops accepting jobs of client and cancels its running ones,
+// call client.amplifierWG.Wait afterwards to drain them
+func (a *amplifier) detach(client *Client) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	client.ampCancel()
+}
+
+// close stops accepting jobs and cancels running ones, call wg.Wait
+// afterwards to drain in-flight jobs
+func (a *amplifier) close() {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if a.closed {
+		return
+	}
+	a.closed = true
+	a.cancel()
+	close(a.jobs)
+}
+
+// amplify queues a ghz run against proxy with given rpc method and
+// request based on AmplifyOptions, report is saved to report store
+func (client *Client) amplify(call string, request interface{}, amp cfg.Amplifier, profile *cfg.Profile) {
+	client.amplifyThen(call, request, amp, func(rep *runner.Report, err error) {
+		client.saveReport(call, amp, profile, rep, err)
+	})
+}
+
+// amplifyThen works like amplify, but calls done in background once
+// ghz run finished instead of saving report
+func (client *Client) amplifyThen(call string, request interface{}, amp cfg.Amplifier, done func(report *runner.Report, err error)) {
+	client.pool.amplifier.submit(&amplifyJob{
+		client:  client,
+		call:    call,
+		request: request,
+		amp:     amp,
+		done:    done,
+	})
+}
+
+// Flush blocks until all queued and running amplify jobs finished
+func (client *Client) Flush() {
+	client.amplifierWG.Wait()
+}
+
+// saveReport stores ghz report with consistency profile of request,
+// failed run is not stored
+func (client *Client) saveReport(call string, amp cfg.Amplifier, profile *cfg.Profile, rep *runner.Report, err error) {
+	if err != nil || rep == nil {
+		return
+	}
+	record := &report.Record{
+		Method:    call,
+		Namespace: client.Collection.GetCollection(),
+		Config: report.RunConfig{
+			Turbo:        profile.Name == cfg.TurboProfile,
+			Profile:      profile.Name,
+			ReadConcern:  profile.ReadConcern,
+			WriteOptions: profile.WriteOptions,
+			Amplify:      *amp,
+		},
+		Report: rep,
+	}
+	path, err := client.reports.Save(record)
+	if err != nil {
+		log.Errorf("%s: save report error with: %s", call, err)
+		return
+	}
+	log.Infof("%s: report saved to %s", call, path)
+}
+
+// run does one ghz load test of given rpc method against proxy, the
+// run is stopped once ctx is done
+func (client *Client) run(ctx context.Context, call string, request interface{}, amp cfg.Amplifier) (report *runner.Report, err error) {
+	options := []runner.Option{
+		runner.WithProtoset(client.ProtoFile),
+		runner.WithData(request),
+	}
+	options = append(options, client.tlsOptions()...)
+	options = append(options, amplifyOptions(amp)...)
+
+	config, err := runner.NewConfig(call, client.Host, options...)
+	if err != nil {
+		return
+	}
+	requester, err := runner.NewRequester(config)
+	if err != nil {
+		return
+	}
+
+	finished := make(chan struct{})
+	defer close(finished)
+	go func() {
+		var timeout <-chan time.Time
+		if amp.Duration > 0 {
+			timeout = time.After(amp.Duration)
+		}
+		select {
+		case <-ctx.Done():
+			requester.Stop(runner.ReasonCancel)
+		case <-timeout:
+			requester.Stop(runner.ReasonTimeout)
+		case <-finished:
+		}
+	}()
+	return requester.Run()
+}
+
+// amplifyOptions converts AmplifyOptions into ghz runner options
+//
+// when Duration is specified, ghz keeps sending requests until duration
+// is reached and TotalRequest is ignored, QPS of 0 means no rate limit,
+// Timeout of 0 keeps ghz default timeout per request
+func amplifyOptions(amp cfg.Amplifier) (options []runner.Option) {
+	options = []runner.Option{
+		runner.WithConcurrency(amp.Concurrency),
+		runner.WithConnections(amp.Connections),
+		runner.WithCPUs(amp.CPUs),
+	}
+	if amp.Duration > 0 {
+		options = append(options, runner.WithRunDuration(amp.Duration))
+	} else if amp.TotalRequest > 0 {
+		options = append(options, runner.WithTotalRequests(amp.TotalRequest))
+	}
+	if amp.QPS > 0 {
+		options = append(options, runner.WithQPS(amp.QPS))
+	}
+	if amp.Timeout > 0 {
+		options = append(options, runner.WithTimeout(amp.Timeout))
+	}
+	return
+}
+
+// tlsOptions converts tls cfg of proxy into ghz runner options, so
+// amplification connects to proxy the same way as client does
+func (client *Client) tlsOptions() []runner.Option {
+	config := client.config
+	if !config.Secure {
+		return []runner.Option{runner.WithInsecure(true)}
+	}
+	options := []runner.Option{runner.WithInsecure(false)}
+	if config.ProxyCA != "" {
+		options = append(options, runner.WithRootCertificate(config.ProxyCA))
+	}
+	if config.ProxyCert != "" {
+		options = append(options, runner.WithCertificate(config.ProxyCert, config.ProxyKey))
+	}
+	if config.ProxyServerName != "" {
+		options = append(options, runner.WithServerNameOverride(config.ProxyServerName))
+	}
+	return options
+}
+
+// scratchCollection returns collection used by amplification, amp
+// documents never land in client collection so undo running in
+// background does not remove documents of real requests, bulk uses its
+// own suffix since its undo empties the whole scratch collection
+func (client *Client) scratchCollection(suffix string) *mprpc.Collection {
+	return &mprpc.Collection{
+		Database:   client.Collection.GetDatabase(),
+		Collection: client.Collection.GetCollection() + suffix,
+	}
+}
+
+// undoInsert removes documents inserted by amplifier from scratch
+// collection, it runs after client ctx cancelled so a fresh ctx is used
+func (client *Client) undoInsert(collection *mprpc.Collection, params []*RemoveParam) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(client.config.RpcTimeout)*time.Millisecond)
+	defer cancel()
+
+	for _, p := range params {
+		b, err := bson.Marshal(p.Filter)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		_, err = client.rpcClient.Remove(ctx, &mprpc.RemoveOperation{
+			Collection:   collection,
+			Filter:       b,
+			Writeoptions: client.profile.WriteOptions,
+		})
+		if err != nil {
+			log.Errorf("%s: undo amp insert error with: %s", Remove, err)
+		}
+	}
+}
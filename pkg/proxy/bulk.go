@@ -0,0 +1,172 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/xidongc-wish/mgo/bson"
+	"github.com/xidongc/mongo_ebenchmark/mprpc"
+)
+
+// BulkResult of a bulk write, Results has one entry per executed
+// operation in order of BulkParam.Ops, operations skipped by an ordered
+// bulk after failure are not included
+type BulkResult struct {
+	Inserted int64
+	Matched  int64
+	Updated  int64
+	Removed  int64
+	Results  []BulkOpResult
+}
+
+// BulkOpResult of a single bulk operation, Index refers to BulkParam.Ops,
+// ChangeInfo is nil if backend only reports totals
+type BulkOpResult struct {
+	Index      int
+	ChangeInfo *mprpc.ChangeInfo
+	Err        error
+}
+
+// Err returns error of the first failed operation, nil if all succeed
+func (r *BulkResult) Err() error {
+	for _, result := range r.Results {
+		if result.Err != nil {
+			return result.Err
+		}
+	}
+	return nil
+}
+
+// Failed returns results of failed operations
+func (r *BulkResult) Failed() (failed []BulkOpResult) {
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return
+}
+
+// add records result of index-th operation and sums up change info
+func (r *BulkResult) add(op BulkOp, index int, changeInfo *mprpc.ChangeInfo, err error) {
+	r.Results = append(r.Results, BulkOpResult{Index: index, ChangeInfo: changeInfo, Err: err})
+	if err != nil {
+		return
+	}
+	if op.Type == BulkInsert {
+		r.Inserted++
+		return
+	}
+	r.Matched += changeInfo.GetMatched()
+	r.Updated += changeInfo.GetUpdated()
+	r.Removed += changeInfo.GetRemoved()
+}
+
+// validateBulk checks operation types and insert documents
+func validateBulk(param *BulkParam) error {
+	if param == nil || len(param.Ops) == 0 {
+		return &Error{Op: Bulk, Kind: ErrInvalid, Err: errors.New("no bulk operation specified")}
+	}
+	for i, op := range param.Ops {
+		switch op.Type {
+		case BulkInsert:
+			if op.Doc == nil {
+				return &Error{Op: Bulk, Kind: ErrInvalid, Err: fmt.Errorf("op %d: insert without document", i)}
+			}
+		case BulkUpdate, BulkUpsert, BulkRemove:
+		default:
+			return &Error{Op: Bulk, Kind: ErrInvalid, Err: fmt.Errorf("op %d: unknown type %d", i, op.Type)}
+		}
+	}
+	return nil
+}
+
+// bulkChunks splits n operations into [start, end) chunks of given size,
+// a single chunk is returned if size is not positive
+func bulkChunks(n int, size int64) (chunks [][2]int) {
+	if size <= 0 {
+		size = int64(n)
+	}
+	for start := 0; start < n; start += int(size) {
+		end := start + int(size)
+		if end > n {
+			end = n
+		}
+		chunks = append(chunks, [2]int{start, end})
+	}
+	return
+}
+
+// bulkOps marshals operations into mprpc.BulkOp
+func bulkOps(ops []BulkOp) (rpcOps []*mprpc.BulkOp, err error) {
+	for i, op := range ops {
+		rpcOp := &mprpc.BulkOp{Multi: op.Multi}
+		switch op.Type {
+		case BulkInsert:
+			rpcOp.Type = mprpc.BulkOp_INSERT
+			rpcOp.Document, err = bson.Marshal(op.Doc)
+		case BulkUpdate, BulkUpsert:
+			rpcOp.Type = mprpc.BulkOp_UPDATE
+			rpcOp.Upsert = op.Type == BulkUpsert
+			if rpcOp.Filter, err = bson.Marshal(op.Filter); err == nil {
+				rpcOp.Update, err = bson.Marshal(op.Update)
+			}
+		case BulkRemove:
+			rpcOp.Type = mprpc.BulkOp_REMOVE
+			rpcOp.Filter, err = bson.Marshal(op.Filter)
+		}
+		if err != nil {
+			return nil, marshalError(Bulk, fmt.Errorf("op %d: %w", i, err))
+		}
+		rpcOps = append(rpcOps, rpcOp)
+	}
+	return
+}
+
+// runBulk runs operations one by one through storage, used by backends
+// without native bulk support
+func runBulk(ctx context.Context, storage Storage, param *BulkParam) (result *BulkResult, err error) {
+	if err = validateBulk(param); err != nil {
+		return
+	}
+	result = &BulkResult{}
+	for i, op := range param.Ops {
+		var changeInfo *mprpc.ChangeInfo
+		var opErr error
+		switch op.Type {
+		case BulkInsert:
+			opErr = storage.Insert(ctx, &InsertParam{Docs: []interface{}{op.Doc}, Profile: param.Profile})
+		case BulkUpdate, BulkUpsert:
+			changeInfo, opErr = storage.Update(ctx, &UpdateParam{
+				Filter:  op.Filter,
+				Update:  op.Update,
+				Upsert:  op.Type == BulkUpsert,
+				Multi:   op.Multi,
+				Profile: param.Profile,
+			})
+		case BulkRemove:
+			changeInfo, opErr = storage.Remove(ctx, &RemoveParam{Filter: op.Filter, Profile: param.Profile})
+		}
+		result.add(op, i, changeInfo, opErr)
+		if opErr != nil && param.Ordered {
+			break
+		}
+	}
+	return result, result.Err()
+}
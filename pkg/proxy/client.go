@@ -18,19 +18,18 @@ package proxy
 
 import (
 	"context"
-	"fmt"
-	"github.com/bojand/ghz/printer"
+	"errors"
 	"github.com/bojand/ghz/runner"
 	log "github.com/sirupsen/logrus"
-	"github.com/xidongc-wish/mgo"
 	"github.com/xidongc-wish/mgo/bson"
 	"github.com/xidongc/mongo_ebenchmark/mprpc"
 	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
-	"google.golang.org/grpc"
-	"os"
+	"github.com/xidongc/mongo_ebenchmark/pkg/metrics"
+	"github.com/xidongc/mongo_ebenchmark/pkg/report"
+	"github.com/xidongc/mongo_ebenchmark/pkg/tracing"
+	"google.golang.org/grpc/status"
 	"sync"
 	"sync/atomic"
-	"time"
 )
 
 // Function List in Proxy
@@ -49,10 +48,14 @@ const (
 	Healthcheck   = "mprpc.MongoProxy.Healthcheck"
 )
 
-// Database used for ebenchmark
+// Database used for ebenchmark, insert amplification writes into
+// collection with ScratchSuffix, bulk amplification into collection with
+// BulkScratchSuffix which is emptied once its run finished
 const (
-	Database   = "ebenchmark"
-	Collection = "default"
+	Database          = "ebenchmark"
+	Collection        = "default"
+	ScratchSuffix     = "_amp"
+	BulkScratchSuffix = "_amp_bulk"
 )
 
 // Mode for FindAndModify
@@ -62,6 +65,14 @@ const (
 	FindAndUpsert FindAndModifyMode = 2
 )
 
+// Operation types for Bulk
+const (
+	BulkInsert BulkOpType = 0
+	BulkUpdate BulkOpType = 1
+	BulkUpsert BulkOpType = 2
+	BulkRemove BulkOpType = 3
+)
+
 // Client represents a middleware with the actual database driver
 //
 // Currently client will use ebenchmark database as defined in const
@@ -71,85 +82,56 @@ type Client struct {
 	config      *cfg.ProxyConfig
 	Host        string
 	Collection  *mprpc.Collection
-	Turbo       bool
-	activeCon   *grpc.ClientConn
+	profile     *cfg.Profile
+	pool        *Pool
+	ownsPool    bool
 	rpcClient   mprpc.MongoProxyClient
 	cancelFunc  context.CancelFunc
 	Healthy     int32
 	ProtoFile   string
-	amplifierWG sync.WaitGroup // add for lock prep work
+	amplifierWG sync.WaitGroup // tracks queued and running amp jobs
+	ampCtx      context.Context
+	ampCancel   context.CancelFunc
+	reports     *report.Store
 }
 
 // NewClient Creates a new proxy client based on provided cfg
 // This method is generally called just once for con establish,
-// does health check in the background task, and check for env:
-// PROTOSET_FILE, this env represent grpc interface with driver
+// and check for env: PROTOSET_FILE, this env represent grpc
+// interface with driver. health of proxy is probed by HealthCheck
+//
+// client dials a Pool of its own, use Pool.Client to share
+// connections across namespaces instead
 //
 // Once Client is not useful anymore, Close must be called to
 // release the resources appropriately
 func NewClient(config *cfg.ProxyConfig, namespace string, cancel context.CancelFunc) (client *Client, err error) {
-	if config == nil {
-		config = cfg.DefaultConfig()
-	}
-	if namespace == "" {
-		namespace = Collection
-	}
-	if config.ProxyPort < 0 || config.ProxyPort == 0 {
-		log.Warning("port not defined properly, set back to default")
-		config.ProxyPort = 50051
-	}
-
-	host := fmt.Sprintf("%s:%d", config.ProxyAddr, config.ProxyPort)
-	client = &Client{
-		config: config,
-		Host:   host,
-	}
-
-	val, ok := os.LookupEnv("PROTOSET_FILE")
-	if !ok {
-		atomic.StoreInt32(&client.Healthy, 0)
-		log.Panic("not found env PROTOSET_FILE")
-		return
-	}
-	client.amplifierWG = sync.WaitGroup{}
-	client.ProtoFile = val
-
-	if client.Collection == nil {
-		client.Collection = &mprpc.Collection{
-			Database:   Database,
-			Collection: namespace,
-		}
+	pool, err := NewPool(config)
+	if err != nil {
+		return nil, err
 	}
-	conn, err := grpc.Dial(host, grpc.WithInsecure())
+	client, err = pool.Client(namespace, cancel)
 	if err != nil {
-		log.Fatalf("connect to rpc cfg error: %s", err)
+		_ = pool.Close()
+		return nil, err
 	}
-	client.activeCon = conn
-	client.rpcClient = mprpc.NewMongoProxyClient(conn)
 	if cancel == nil {
 		log.Warning("handle context exit in application")
 	}
-	client.cancelFunc = cancel
-
-	go func() {
-		for {
-			_ = client.HealthCheck()
-			if atomic.LoadInt32(&client.Healthy) != 1 {
-				panic("cfg not healthy error")
-			}
-			time.Sleep(time.Second * 300)
-		}
-	}()
+	client.ownsPool = true
 	return
 }
 
 // Close will release the resources in a proxy client, and call
-// cancelFunc if specified
+// cancelFunc if specified, running amp jobs are cancelled and
+// drained, connections are closed only if client owns its pool
 //
 // Call Close after NewClient, See NewClient for more details
 func (client *Client) Close() (err error) {
-	if err := client.activeCon.Close(); err != nil {
-		log.Fatalf("clean up failed: %s", err)
+	client.pool.amplifier.detach(client)
+	client.amplifierWG.Wait()
+	if client.ownsPool {
+		err = client.pool.Close()
 	}
 	if client.cancelFunc != nil {
 		client.cancelFunc()
@@ -167,76 +149,36 @@ func (client *Client) Close() (err error) {
 //     http://www.mongodb.org/display/DOCS/Advanced+Queries
 //
 func (client *Client) Find(ctx context.Context, query *QueryParam) (docs []bson.M, err error) {
-	filterBytes, err := bson.Marshal(query.Filter)
+	ctx, done := client.observe(ctx, Find, query.Profile, query.Filter)
+	defer done(&err)
+	profile, err := client.consistency(ctx, Find, query.Profile)
 	if err != nil {
-		log.Errorf("%s: marshal filter error", Find)
-	}
-	var readConcern string
-	var prefetch float64
-	var readPref mgo.Mode
-
-	if client.Turbo {
-		readConcern = "local"
-		prefetch = 0.75
-		readPref = mgo.Nearest
-	} else {
-		readConcern = "linearizable"
-		prefetch = 0.25
-		readPref = mgo.Primary
+		return nil, err
 	}
-
-	// TODO add params
-	request := mprpc.FindQuery{
-		Collection:  client.Collection,
-		Filter:      filterBytes,
-		Skip:        query.Skip,
-		Maxtimems:   -1,
-		Maxscan:     0,
-		Prefetch:    prefetch,
-		Batchsize:   client.config.BatchSize,
-		Readpref:    int32(readPref),
-		Findone:     query.FindOne,
-		Partial:     client.config.AllowPartial,
-		Readconcern: readConcern,
-		Comment:     FindIter,
-		Rpctimeout:  client.config.RpcTimeout,
-		Hint:        query.UsingIndex,
+	request, err := client.findQuery(profile, query, Find)
+	if err != nil {
+		log.Errorf("%s: marshal filter error", Find)
+		return nil, marshalError(Find, err)
 	}
 
 	if query.Amp != nil {
-		report, err := runner.Run(
-			Find,
-			client.Host,
-			runner.WithProtoset(client.ProtoFile),
-			runner.WithConcurrency(query.Amp.Concurrency),
-			runner.WithConnections(query.Amp.Connections),
-			runner.WithCPUs(query.Amp.CPUs),
-			runner.WithData(request),
-			runner.WithInsecure(true),
-		)
-		if err != nil {
-			log.Fatal(err.Error())
-		}
-		file, err := os.Create("results/test_find.html")
-		p := printer.ReportPrinter{
-			Out:    file,
-			Report: report,
-		}
-
-		_ = p.Print("html")
+		client.amplify(Find, request, query.Amp, profile)
 	}
 
-	resultSet, err := client.rpcClient.Find(ctx, &request)
+	resultSet, err := client.rpcClient.Find(ctx, request)
 	if err != nil {
-		log.Error(err)
+		log.Errorf("rpc find error with: %s", err)
+		return nil, rpcError(Find, err)
 	}
-	for _, r := range resultSet.Results {
+	for _, r := range resultSet.GetResults() {
 		var doc bson.M
 		if err = bson.Unmarshal(r.Val, &doc); err != nil {
 			log.Error(err)
+			return nil, marshalError(Find, err)
 		}
 		docs = append(docs, doc)
 	}
+	traceDocuments(ctx, int64(len(docs)))
 	return
 }
 
@@ -250,70 +192,57 @@ func (client *Client) Find(ctx context.Context, query *QueryParam) (docs []bson.
 //     http://www.mongodb.org/display/DOCS/Advanced+Queries
 //
 func (client *Client) FindIter(ctx context.Context, query *QueryParam) (stream mprpc.MongoProxy_FindIterClient, err error) {
-	filterBytes, err := bson.Marshal(query.Filter)
+	ctx, done := client.observe(ctx, FindIter, query.Profile, query.Filter)
+	defer done(&err)
+	profile, err := client.consistency(ctx, FindIter, query.Profile)
 	if err != nil {
-		log.Errorf("%s: marshall filter error", FindIter)
-	}
-	var readConcern string
-	var prefetch float64
-	var readPref mgo.Mode
-
-	if client.Turbo {
-		readConcern = "local"
-		prefetch = 0.75
-		readPref = mgo.Nearest
-	} else {
-		readConcern = "linearizable"
-		prefetch = 0.25
-		readPref = mgo.Primary
-	}
-
-	request := mprpc.FindQuery{
-		Collection:  client.Collection,
-		Filter:      filterBytes,
-		Skip:        0,
-		Maxtimems:   -1,
-		Maxscan:     0,
-		Prefetch:    prefetch,
-		Batchsize:   client.config.BatchSize,
-		Readpref:    int32(readPref),
-		Findone:     false,
-		Partial:     client.config.AllowPartial,
-		Readconcern: readConcern,
-		Comment:     FindIter,
-		Rpctimeout:  client.config.RpcTimeout,
+		return nil, err
 	}
-	stream, err = client.rpcClient.FindIter(ctx, &request)
+	request, err := client.findQuery(profile, query, FindIter)
 	if err != nil {
-		log.Fatalf("find iter call failed with: %s", err)
-		return
+		log.Errorf("%s: marshall filter error", FindIter)
+		return nil, marshalError(FindIter, err)
 	}
-	report, err := runner.Run(
-		FindIter,
-		client.Host,
-		runner.WithProtoset(client.ProtoFile),
-		runner.WithConcurrency(query.Amp.Concurrency),
-		runner.WithConnections(query.Amp.Connections),
-		runner.WithCPUs(query.Amp.CPUs),
-		runner.WithData(request),
-		runner.WithInsecure(true),
-	)
+
+	stream, err = client.rpcClient.FindIter(ctx, request)
 	if err != nil {
-		log.Fatal(err.Error())
-	}
-	p := printer.ReportPrinter{
-		Out:    os.Stdout,
-		Report: report,
+		log.Errorf("find iter call failed with: %s", err)
+		return nil, rpcError(FindIter, err)
 	}
 
-	_ = p.Print("pretty")
+	if query.Amp != nil {
+		client.amplify(FindIter, request, query.Amp, profile)
+	}
 	return
 }
 
-// Count returns the total number of documents in the collection.
+// Count returns the number of documents in the collection matching filter.
 //
 // See proxy.QueryParam for customizing query param
 func (client *Client) Count(ctx context.Context, query *QueryParam) (count uint64, err error) {
+	ctx, done := client.observe(ctx, Count, query.Profile, query.Filter)
+	defer done(&err)
+	profile, err := client.consistency(ctx, Count, query.Profile)
+	if err != nil {
+		return 0, err
+	}
+	request, err := client.findQuery(profile, query, Count)
+	if err != nil {
+		log.Errorf("%s: marshal filter error", Count)
+		return 0, marshalError(Count, err)
+	}
+
+	if query.Amp != nil {
+		client.amplify(Count, request, query.Amp, profile)
+	}
+
+	result, err := client.rpcClient.Count(ctx, request)
+	if err != nil {
+		log.Errorf("rpc count error with: %s", err)
+		return 0, rpcError(Count, err)
+	}
+	count = uint64(result.GetCount())
+	traceDocuments(ctx, result.GetCount())
 	return
 }
 
@@ -330,6 +259,31 @@ func (client *Client) Count(ctx context.Context, query *QueryParam) (count uint6
 //     http://www.mongodb.org/display/DOCS/Query+Optimizer
 //
 func (client *Client) Explain(ctx context.Context, query *QueryParam) (explainFields bson.M, err error) {
+	ctx, done := client.observe(ctx, Explain, query.Profile, query.Filter)
+	defer done(&err)
+	profile, err := client.consistency(ctx, Explain, query.Profile)
+	if err != nil {
+		return nil, err
+	}
+	request, err := client.findQuery(profile, query, Explain)
+	if err != nil {
+		log.Errorf("%s: marshal filter error", Explain)
+		return nil, marshalError(Explain, err)
+	}
+
+	if query.Amp != nil {
+		client.amplify(Explain, request, query.Amp, profile)
+	}
+
+	result, err := client.rpcClient.Explain(ctx, request)
+	if err != nil {
+		log.Errorf("rpc explain error with: %s", err)
+		return nil, rpcError(Explain, err)
+	}
+	if err = bson.Unmarshal(result.GetVal(), &explainFields); err != nil {
+		log.Error(err)
+		return nil, marshalError(Explain, err)
+	}
 	return
 }
 
@@ -347,6 +301,51 @@ func (client *Client) Explain(ctx context.Context, query *QueryParam) (explainFi
 //     http://docs.mongodb.org/manual/tutorial/aggregation-examples
 //
 func (client *Client) Aggregate(ctx context.Context, query *AggregateParam) (documents []interface{}, err error) {
+	ctx, done := client.observe(ctx, Aggregate, query.Profile, query.Pipeline)
+	defer done(&err)
+	var pipeline [][]byte
+	for _, stage := range query.Pipeline {
+		stageBytes, err := bson.Marshal(stage)
+		if err != nil {
+			log.Errorf("%s: marshal pipeline error", Aggregate)
+			return nil, marshalError(Aggregate, err)
+		}
+		pipeline = append(pipeline, stageBytes)
+	}
+
+	profile, err := client.consistency(ctx, Aggregate, query.Profile)
+	if err != nil {
+		return nil, err
+	}
+	request := &mprpc.AggregateQuery{
+		Collection:  client.Collection,
+		Pipeline:    pipeline,
+		Prefetch:    profile.Prefetch,
+		Batchsize:   profile.BatchSize,
+		Readpref:    int32(profile.ReadPref),
+		Readconcern: profile.ReadConcern,
+		Comment:     Aggregate,
+		Rpctimeout:  client.config.RpcTimeout,
+	}
+
+	if query.Amp != nil {
+		client.amplify(Aggregate, request, query.Amp, profile)
+	}
+
+	resultSet, err := client.rpcClient.Aggregate(ctx, request)
+	if err != nil {
+		log.Errorf("rpc aggregate error with: %s", err)
+		return nil, rpcError(Aggregate, err)
+	}
+	for _, r := range resultSet.GetResults() {
+		var doc bson.M
+		if err = bson.Unmarshal(r.Val, &doc); err != nil {
+			log.Error(err)
+			return nil, marshalError(Aggregate, err)
+		}
+		documents = append(documents, doc)
+	}
+	traceDocuments(ctx, int64(len(documents)))
 	return
 }
 
@@ -361,20 +360,22 @@ func (client *Client) Aggregate(ctx context.Context, query *AggregateParam) (doc
 //     http://www.mongodb.org/display/DOCS/Atomic+Operations
 //
 func (client *Client) Update(ctx context.Context, param *UpdateParam) (changeInfo *mprpc.ChangeInfo, err error) {
-	var wOptions *mprpc.WriteOptions
-	if client.Turbo {
-		wOptions = cfg.GetTurboWriteOptions()
-	} else {
-		wOptions = cfg.GetSafeWriteOptions()
+	ctx, done := client.observe(ctx, Update, param.Profile, param.Filter)
+	defer done(&err)
+	profile, err := client.consistency(ctx, Update, param.Profile)
+	if err != nil {
+		return nil, err
 	}
 
 	filter, err := bson.Marshal(param.Filter)
 	if err != nil {
-		log.Fatal(err)
+		log.Error(err)
+		return nil, marshalError(Update, err)
 	}
 	update, err := bson.Marshal(param.Update)
 	if err != nil {
-		log.Fatal(err)
+		log.Error(err)
+		return nil, marshalError(Update, err)
 	}
 
 	request := &mprpc.UpdateOperation{
@@ -383,13 +384,15 @@ func (client *Client) Update(ctx context.Context, param *UpdateParam) (changeInf
 		Update:       update,
 		Upsert:       param.Upsert,
 		Multi:        param.Multi,
-		Writeoptions: wOptions,
+		Writeoptions: profile.WriteOptions,
 	}
 
 	changeInfo, err = client.rpcClient.Update(ctx, request)
 	if err != nil {
-		log.Fatal(err)
+		log.Error(err)
+		return nil, rpcError(Update, err)
 	}
+	traceDocuments(ctx, changeInfo.GetMatched())
 	return
 }
 
@@ -403,26 +406,27 @@ func (client *Client) Update(ctx context.Context, param *UpdateParam) (changeInf
 //     http://www.mongodb.org/display/DOCS/Removing
 //
 func (client *Client) Remove(ctx context.Context, param *RemoveParam) (changeInfo *mprpc.ChangeInfo, err error) {
+	ctx, done := client.observe(ctx, Remove, param.Profile, param.Filter)
+	defer done(&err)
 	b, err := bson.Marshal(param.Filter)
 	if err != nil {
 		log.Error(err)
-		return
+		return nil, marshalError(Remove, err)
 	}
-	var wOptions *mprpc.WriteOptions
-	if client.Turbo {
-		wOptions = cfg.GetTurboWriteOptions()
-	} else {
-		wOptions = cfg.GetSafeWriteOptions()
+	profile, err := client.consistency(ctx, Remove, param.Profile)
+	if err != nil {
+		return nil, err
 	}
 	removeOps := &mprpc.RemoveOperation{
 		Collection:   client.Collection,
 		Filter:       b,
-		Writeoptions: wOptions,
+		Writeoptions: profile.WriteOptions,
 	}
-	log.Info(removeOps)
 	if changeInfo, err = client.rpcClient.Remove(ctx, removeOps); err != nil {
 		log.Error(err)
+		return nil, rpcError(Remove, err)
 	}
+	traceDocuments(ctx, changeInfo.GetRemoved())
 	return
 }
 
@@ -435,70 +439,135 @@ func (client *Client) Remove(ctx context.Context, param *RemoveParam) (changeInf
 // http://www.mongodb.org/display/DOCS/Inserting
 //
 func (client *Client) Insert(ctx context.Context, param *InsertParam) (err error) {
-
+	ctx, done := client.observe(ctx, Insert, param.Profile, nil)
+	defer done(&err)
 	var rpcDocs []*mprpc.Document
 	for _, doc := range param.Docs {
 		val, err := bson.Marshal(doc)
 		if err != nil {
-			log.Panicf("unable to marshall error: %s", err)
+			log.Errorf("unable to marshall error: %s", err)
+			return marshalError(Insert, err)
 		}
 		rpcDocs = append(rpcDocs, &mprpc.Document{
 			Val: val,
 		})
 	}
-	var wOptions *mprpc.WriteOptions
-	if client.Turbo {
-		wOptions = cfg.GetTurboWriteOptions()
-	} else {
-		wOptions = cfg.GetSafeWriteOptions()
+	profile, err := client.consistency(ctx, Insert, param.Profile)
+	if err != nil {
+		return err
 	}
 	request := mprpc.InsertOperation{
 		Collection:   client.Collection,
 		Documents:    rpcDocs,
-		Writeoptions: wOptions,
+		Writeoptions: profile.WriteOptions,
 	}
 
 	if param.Amp != nil {
-		client.amplifierWG.Add(1)
-		report, err := runner.Run(
-			Insert,
-			client.Host,
-			runner.WithProtoset(client.ProtoFile),
-			runner.WithConcurrency(param.Amp.Concurrency),
-			runner.WithConnections(param.Amp.Connections),
-			runner.WithCPUs(param.Amp.CPUs),
-			runner.WithData(request),
-			runner.WithInsecure(!client.config.Secure),
-		)
-		if err != nil {
-			log.Fatal(err.Error())
-		}
-		p := printer.ReportPrinter{
-			Out:    os.Stdout,
-			Report: report,
+		scratch := client.scratchCollection(ScratchSuffix)
+		ampRequest := &mprpc.InsertOperation{
+			Collection:   scratch,
+			Documents:    rpcDocs,
+			Writeoptions: profile.WriteOptions,
 		}
-
-		_ = p.Print("pretty")
-
-		removeParams := UndoInsert(param)
-		for _, p := range removeParams {
-			if _, err := client.Remove(ctx, p); err != nil {
-				log.Error(err)
-			}
-		}
-		client.amplifierWG.Done()
-
-	} else {
-		log.Info("no amp specified")
+		client.amplifyThen(Insert, ampRequest, param.Amp, func(report *runner.Report, err error) {
+			client.saveReport(Insert, param.Amp, profile, report, err)
+			client.undoInsert(scratch, UndoInsert(param))
+		})
 	}
 
 	if _, err = client.rpcClient.Insert(ctx, &request); err != nil {
 		log.Errorf("rpc insert error with: %s", err)
-		return
+		return rpcError(Insert, err)
 	}
+	traceDocuments(ctx, int64(len(rpcDocs)))
 	return
 }
 
+// Bulk runs a mix of inserts, updates, upserts and removes, operations
+// are sent in chunks of batch size of consistency profile. Ordered bulk
+// stops at the first failed operation, unordered bulk runs all of them.
+//
+// See proxy.BulkParam for customizing bulk param, result of every
+// executed operation is reported in BulkResult, err is the first error.
+// Amplification replays the first chunk against scratch collection
+//
+// Relevant documentation:
+//
+//     https://docs.mongodb.com/manual/core/bulk-write-operations/
+//
+func (client *Client) Bulk(ctx context.Context, param *BulkParam) (result *BulkResult, err error) {
+	ctx, done := client.observe(ctx, Bulk, param.Profile, nil)
+	defer done(&err)
+	if err = validateBulk(param); err != nil {
+		return
+	}
+	profile, err := client.consistency(ctx, Bulk, param.Profile)
+	if err != nil {
+		return nil, err
+	}
+	ops, err := bulkOps(param.Ops)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	result = &BulkResult{}
+	for i, chunk := range bulkChunks(len(ops), profile.BatchSize) {
+		start, end := chunk[0], chunk[1]
+		request := &mprpc.BulkOperation{
+			Collection:   client.Collection,
+			Ops:          ops[start:end],
+			Ordered:      param.Ordered,
+			Writeoptions: profile.WriteOptions,
+		}
+
+		if i == 0 && param.Amp != nil {
+			scratch := client.scratchCollection(BulkScratchSuffix)
+			ampRequest := &mprpc.BulkOperation{
+				Collection:   scratch,
+				Ops:          request.Ops,
+				Ordered:      param.Ordered,
+				Writeoptions: profile.WriteOptions,
+			}
+			client.amplifyThen(Bulk, ampRequest, param.Amp, func(report *runner.Report, err error) {
+				client.saveReport(Bulk, param.Amp, profile, report, err)
+				client.undoInsert(scratch, []*RemoveParam{{Filter: bson.M{}}})
+			})
+		}
+
+		reply, rpcErr := client.rpcClient.Bulk(ctx, request)
+		if rpcErr != nil {
+			log.Errorf("rpc bulk error with: %s", rpcErr)
+			rpcErr = rpcError(Bulk, rpcErr)
+			for index := start; index < end; index++ {
+				result.add(param.Ops[index], index, nil, rpcErr)
+			}
+			if param.Ordered {
+				break
+			}
+			continue
+		}
+		failed := false
+		for _, opResult := range reply.GetResults() {
+			index := start + int(opResult.GetIndex())
+			if index < start || index >= end {
+				log.Warnf("%s: unexpected op index %d", Bulk, opResult.GetIndex())
+				continue
+			}
+			var opErr error
+			if opResult.GetError() != "" {
+				opErr, failed = &Error{Op: Bulk, Err: errors.New(opResult.GetError())}, true
+			}
+			result.add(param.Ops[index], index, opResult.GetChangeinfo(), opErr)
+		}
+		if failed && param.Ordered {
+			break
+		}
+	}
+	traceDocuments(ctx, result.Inserted+result.Matched+result.Removed)
+	return result, result.Err()
+}
+
 // FindAndModify allows updating, upserting or removing a document matching
 // a query and atomically returning either the old version (the default) or
 // the new version of the document (when ReturnNew is true). If no objects
@@ -512,39 +581,61 @@ func (client *Client) Insert(ctx context.Context, param *InsertParam) (err error
 //     http://www.mongodb.org/display/DOCS/Updating
 //     http://www.mongodb.org/display/DOCS/Atomic+Operations
 //
-func (client *Client) FindAndModify(ctx context.Context, param *FindModifyParam) (singleDoc interface{}, err error) {
+func (client *Client) FindAndModify(ctx context.Context, param *FindModifyParam) (singleDoc bson.M, err error) {
+	ctx, done := client.observe(ctx, FindAndModify, param.Profile, param.Filter)
+	defer done(&err)
 	filterBytes, err := bson.Marshal(param.Filter)
 	if err != nil {
 		log.Errorf("%s: marshall filter error", FindAndModify)
+		return nil, marshalError(FindAndModify, err)
 	}
 
-	updateBytes, err := bson.Marshal(param.Desired)
-	if err != nil {
-		log.Errorf("%s: marshall filter error", FindAndModify)
+	var updateBytes []byte
+	if param.Mode != FindAndDelete {
+		if updateBytes, err = bson.Marshal(param.Desired); err != nil {
+			log.Errorf("%s: marshall update error", FindAndModify)
+			return nil, marshalError(FindAndModify, err)
+		}
 	}
 
-	var wOptions *mprpc.WriteOptions
-	if client.Turbo {
-		wOptions = cfg.GetTurboWriteOptions()
-	} else {
-		wOptions = cfg.GetSafeWriteOptions()
+	var fieldsBytes []byte
+	if param.Fields != nil {
+		if fieldsBytes, err = bson.Marshal(param.Fields); err != nil {
+			log.Errorf("%s: marshall fields error", FindAndModify)
+			return nil, marshalError(FindAndModify, err)
+		}
+	}
+
+	profile, err := client.consistency(ctx, FindAndModify, param.Profile)
+	if err != nil {
+		return nil, err
 	}
 
-	// TODO add params back
 	request := mprpc.FindAndModifyOperation{
 		Collection:   client.Collection,
 		Filter:       filterBytes,
 		Update:       updateBytes,
-		Upsert:       true,
-		New:          true,
-		Fields:       nil,
-		Writeoptions: wOptions,
+		Upsert:       param.Mode == FindAndUpsert,
+		Remove:       param.Mode == FindAndDelete,
+		New:          param.Mode != FindAndDelete,
+		Fields:       fieldsBytes,
+		Writeoptions: profile.WriteOptions,
 	}
 
-	singleDoc, err = client.rpcClient.FindAndModify(ctx, &request)
-
-	log.Info(singleDoc)
-
+	result, err := client.rpcClient.FindAndModify(ctx, &request)
+	if err != nil {
+		log.Errorf("rpc findAndModify error with: %s", err)
+		return nil, rpcError(FindAndModify, err)
+	}
+	if len(result.GetVal()) == 0 {
+		traceDocuments(ctx, 0)
+		return
+	}
+	if err = bson.Unmarshal(result.GetVal(), &singleDoc); err != nil {
+		log.Error(err)
+		return nil, marshalError(FindAndModify, err)
+	}
+	traceDocuments(ctx, 1)
 	return
 }
 
@@ -557,25 +648,132 @@ func (client *Client) FindAndModify(ctx context.Context, param *FindModifyParam)
 //     http://www.mongodb.org/display/DOCS/Aggregation
 //
 func (client *Client) Distinct(ctx context.Context, query *QueryParam) (distinctKeys []interface{}, err error) {
+	ctx, done := client.observe(ctx, Distinct, query.Profile, query.Filter)
+	defer done(&err)
+	if query.Distinctkey == "" {
+		return nil, &Error{Op: Distinct, Kind: ErrInvalid, Err: errors.New("distinct key not specified")}
+	}
+	profile, err := client.consistency(ctx, Distinct, query.Profile)
+	if err != nil {
+		return nil, err
+	}
+	request, err := client.findQuery(profile, query, Distinct)
+	if err != nil {
+		log.Errorf("%s: marshal filter error", Distinct)
+		return nil, marshalError(Distinct, err)
+	}
+
+	if query.Amp != nil {
+		client.amplify(Distinct, request, query.Amp, profile)
+	}
+
+	result, err := client.rpcClient.Distinct(ctx, request)
+	if err != nil {
+		log.Errorf("rpc distinct error with: %s", err)
+		return nil, rpcError(Distinct, err)
+	}
+
+	// distinct values are wrapped as {values: [...]}, since bson
+	// document can not be an array at top level
+	var doc struct {
+		Values []interface{} `bson:"values"`
+	}
+	if err = bson.Unmarshal(result.GetVal(), &doc); err != nil {
+		log.Error(err)
+		return nil, marshalError(Distinct, err)
+	}
+	distinctKeys = doc.Values
+	traceDocuments(ctx, int64(len(distinctKeys)))
 	return
 }
 
-// HealthCheck checks database driver, and atomically update client
-func (client *Client) HealthCheck() (err error) {
-	log.Info("Start doing health check")
-	empty := Empty{}
-	_, err = runner.Run(
-		Healthcheck,
-		client.Host,
-		runner.WithProtoset(client.ProtoFile),
-		runner.WithData(empty),
-		runner.WithInsecure(true),
-	)
+// findQuery generates mprpc.FindQuery based on given query param, used
+// by Find, FindIter, Count, Distinct and Explain, comment is used to
+// identify caller
+func (client *Client) findQuery(profile *cfg.Profile, query *QueryParam, comment string) (request *mprpc.FindQuery, err error) {
+	filterBytes, err := bson.Marshal(query.Filter)
 	if err != nil {
+		return
+	}
+	var fieldsBytes []byte
+	if query.Fields != nil {
+		if fieldsBytes, err = bson.Marshal(query.Fields); err != nil {
+			return
+		}
+	}
+	request = &mprpc.FindQuery{
+		Collection:  client.Collection,
+		Filter:      filterBytes,
+		Fields:      fieldsBytes,
+		Skip:        query.Skip,
+		Limit:       query.Limit,
+		Sort:        query.Sort,
+		Distinctkey: query.Distinctkey,
+		Maxtimems:   -1,
+		Maxscan:     0,
+		Prefetch:    profile.Prefetch,
+		Batchsize:   profile.BatchSize,
+		Readpref:    int32(profile.ReadPref),
+		Findone:     query.FindOne,
+		Partial:     client.config.AllowPartial,
+		Readconcern: profile.ReadConcern,
+		Comment:     comment,
+		Rpctimeout:  client.config.RpcTimeout,
+		Hint:        query.UsingIndex,
+	}
+	return
+}
+
+// observe records a proxy call in metrics, labelled by namespace and
+// consistency profile the call runs with, and traces it in a client span
+// carrying namespace and filter shape. span is sent to proxy by grpc
+// metadata of returned ctx, eg:
+//
+//     ctx, done := client.observe(ctx, Find, query.Profile, query.Filter)
+//     defer done(&err)
+//
+func (client *Client) observe(ctx context.Context, op string, profile string, filter interface{}) (context.Context, func(err *error)) {
+	name := client.profileLabel(ctx, profile)
+	namespace := client.Collection.GetCollection()
+	done := metrics.ProxyCall(op, namespace, name)
+
+	ctx, span := tracing.Start(ctx, op, tracing.Client)
+	if span.IsRecording() {
+		span.SetAttributes(attrNamespace.String(namespace))
+		if filter != nil {
+			span.SetAttributes(attrFilter.String(filterShape(filter)))
+		}
+	}
+	return tracing.Inject(ctx), func(err *error) {
+		rpcErr := ToStatus(*err)
+		done(status.Code(rpcErr))
+		tracing.End(span, rpcErr)
+	}
+}
+
+// profileLabel returns name of consistency profile a request resolves to,
+// client profile if it selects none or an unknown one, so metric labels
+// are never taken from callers unchecked
+func (client *Client) profileLabel(ctx context.Context, name string) string {
+	name = profileName(ctx, name)
+	if name == "" || name == client.profile.Name {
+		return client.profile.Name
+	}
+	profile, err := client.config.NamedProfile(name)
+	if err != nil {
+		return client.profile.Name
+	}
+	return profile.Name
+}
+
+// HealthCheck pings proxy by a single Healthcheck rpc on client
+// connection, and atomically update client
+func (client *Client) HealthCheck(ctx context.Context) (err error) {
+	if _, err = client.rpcClient.Healthcheck(ctx, &mprpc.Empty{}); err != nil {
 		atomic.StoreInt32(&client.Healthy, 0)
-		log.Fatal(err.Error())
-	} else {
-		atomic.StoreInt32(&client.Healthy, 1)
+		log.Errorf("proxy %s health check error: %s", client.Host, err)
+		return &Error{Op: Healthcheck, Kind: ErrUnhealthy, Err: err}
 	}
+	atomic.StoreInt32(&client.Healthy, 1)
 	return
 }
@@ -17,17 +17,23 @@
 package proxy
 
 import (
-	"context"
+	"errors"
 	"github.com/xidongc-wish/mgo/bson"
 	"github.com/xidongc/mongo_ebenchmark/model/sku/skupb"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"github.com/xidongc/mongo_ebenchmark/pkg/proxy/proxytest"
+	"google.golang.org/grpc/metadata"
 	"testing"
 	"time"
 )
 
 // Test on basic proxy operations
 func TestClientOps(t *testing.T) {
-	ctx, cancel := context.WithCancel(context.Background())
-	client, _ := NewClient(nil, "test", cancel)
+	server, ctx, cancel := proxytest.Start(t)
+	client, err := NewClient(server.Config(), "test", cancel)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	defer func() {
 		err := client.Close()
@@ -36,7 +42,7 @@ func TestClientOps(t *testing.T) {
 		}
 	}()
 
-	if err := client.HealthCheck(); err != nil {
+	if err := client.HealthCheck(ctx); err != nil {
 		panic(err)
 	}
 
@@ -51,17 +57,24 @@ func TestClientOps(t *testing.T) {
 		skus[0] = sku
 		param := &InsertParam{
 			Docs: skus,
-			Amp:  MicroAmplifier(),
+			Amp:  cfg.MicroAmplifier(),
 		}
 		if err := client.Insert(ctx, param); err != nil {
 			t.Error("error")
 		}
+		client.Flush()
+		if docs := server.Docs(Database, "test"+ScratchSuffix); len(docs) != 0 {
+			t.Errorf("expect amp documents removed, got %d", len(docs))
+		}
+		if docs := server.Docs(Database, "test"); len(docs) != 1 {
+			t.Errorf("expect 1 document inserted, got %d", len(docs))
+		}
 	})
 
 	t.Run(FindIter, func(t *testing.T) {
 		queryParam := &QueryParam{
 			Filter: bson.M{},
-			Amp:    MicroAmplifier(),
+			Amp:    cfg.MicroAmplifier(),
 		}
 
 		var documents []interface{}
@@ -102,14 +115,128 @@ func TestClientOps(t *testing.T) {
 	t.Run(Update, func(t *testing.T) {
 		updateParam := &UpdateParam{
 			Filter: bson.M{"skuid": 123},
-			Update: bson.M{"skuid": 124},
+			Update: bson.M{"$set": bson.M{"skuid": 124}},
 			Multi:  false,
-			Amp:    MicroAmplifier(),
+			Amp:    cfg.MicroAmplifier(),
 		}
 		changeInfo, err := client.Update(ctx, updateParam)
 		if err != nil {
 			t.Error(err)
 		}
 		t.Log(changeInfo)
+		if docs := server.Docs(Database, "test"); len(docs) != 1 {
+			t.Errorf("expect 1 document after undo, got %d", len(docs))
+		}
+	})
+
+	t.Run(Count, func(t *testing.T) {
+		queryParam := &QueryParam{
+			Filter: bson.M{"skuid": 124},
+			Amp:    cfg.MicroAmplifier(),
+		}
+		count, err := client.Count(ctx, queryParam)
+		if err != nil {
+			t.Error(err)
+		}
+		if count != 1 {
+			t.Errorf("expect count 1, got %d", count)
+		}
+	})
+
+	t.Run(Distinct, func(t *testing.T) {
+		queryParam := &QueryParam{
+			Filter:      bson.M{},
+			Distinctkey: "warehouseid",
+			Amp:         cfg.MicroAmplifier(),
+		}
+		keys, err := client.Distinct(ctx, queryParam)
+		if err != nil {
+			t.Error(err)
+		}
+		if len(keys) != 1 {
+			t.Errorf("expect 1 distinct key, got %v", keys)
+		}
+		if _, err = client.Distinct(ctx, &QueryParam{Filter: bson.M{}}); !errors.Is(err, ErrInvalid) {
+			t.Errorf("expect invalid distinct error, got %v", err)
+		}
+	})
+
+	t.Run("Profile", func(t *testing.T) {
+		queryParam := &QueryParam{
+			Filter:  bson.M{"skuid": 124},
+			Profile: cfg.MajorityReadProfile,
+		}
+		if count, err := client.Count(WithProfile(ctx, cfg.CausalProfile), queryParam); err != nil || count != 1 {
+			t.Errorf("expect count 1, got %d with %v", count, err)
+		}
+		queryParam.Profile = "eventual"
+		if _, err := client.Count(ctx, queryParam); !errors.Is(err, ErrInvalid) {
+			t.Errorf("expect invalid profile error, got %v", err)
+		}
+
+		// metrics are labelled by resolved profile, unknown names fall back
+		// to client profile
+		incoming := metadata.NewIncomingContext(ctx, metadata.Pairs(ProfileMetadataKey, "eventual"))
+		if name := client.profileLabel(incoming, ""); name != client.profile.Name {
+			t.Errorf("expect client profile label, got %s", name)
+		}
+		if name := client.profileLabel(ctx, cfg.CausalProfile); name != cfg.CausalProfile {
+			t.Errorf("expect %s label, got %s", cfg.CausalProfile, name)
+		}
+	})
+
+	t.Run(Bulk, func(t *testing.T) {
+		config := server.Config()
+		config.BatchSize = 2
+		bulkClient, err := NewClient(config, "bulk", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer bulkClient.Close()
+
+		ops := []BulkOp{
+			{Type: BulkInsert, Doc: bson.M{"name": "mavic", "quantity": 1}},
+			{Type: BulkInsert, Doc: bson.M{"name": "care", "quantity": 2}},
+			{Type: BulkUpdate, Filter: bson.M{"name": "mavic"}, Update: bson.M{"$inc": bson.M{"quantity": 1}}},
+			{Type: BulkUpdate, Filter: bson.M{"name": "care"}, Update: bson.M{"$bogus": 1}},
+			{Type: BulkUpsert, Filter: bson.M{"name": "goggles"}, Update: bson.M{"$set": bson.M{"quantity": 5}}},
+			{Type: BulkRemove, Filter: bson.M{"name": "care"}},
+		}
+		result, err := bulkClient.Bulk(ctx, &BulkParam{Ops: ops, Ordered: true})
+		if err == nil || len(result.Results) != 4 || result.Inserted != 2 || result.Updated != 1 {
+			t.Errorf("expect ordered bulk to stop at op 3, got %+v with %v", result, err)
+		}
+		if failed := result.Failed(); len(failed) != 1 || failed[0].Index != 3 {
+			t.Errorf("unexpected failed ops %+v", failed)
+		}
+
+		// documents left by insert amplification survive bulk undo
+		ampClient, err := NewClient(config, "bulk"+ScratchSuffix, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ampClient.Close()
+		if err = ampClient.Insert(ctx, &InsertParam{Docs: []interface{}{bson.M{"name": "amp"}}}); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err = bulkClient.Bulk(ctx, &BulkParam{Ops: ops[2:], Ordered: false, Amp: cfg.MicroAmplifier()})
+		if err == nil || len(result.Results) != 4 || result.Removed != 1 || len(result.Failed()) != 1 {
+			t.Errorf("expect unordered bulk to run all ops, got %+v with %v", result, err)
+		}
+		bulkClient.Flush()
+		if docs := server.Docs(Database, "bulk"); len(docs) != 2 {
+			t.Errorf("expect mavic and goggles left, got %v", docs)
+		}
+		if docs := server.Docs(Database, "bulk"+BulkScratchSuffix); len(docs) != 0 {
+			t.Errorf("expect empty scratch collection, got %d docs", len(docs))
+		}
+		if docs := server.Docs(Database, "bulk"+ScratchSuffix); len(docs) != 1 {
+			t.Errorf("expect insert scratch collection untouched, got %d docs", len(docs))
+		}
+
+		if _, err = bulkClient.Bulk(ctx, &BulkParam{Ops: []BulkOp{{Type: BulkInsert}}}); !errors.Is(err, ErrInvalid) {
+			t.Errorf("expect invalid bulk error, got %v", err)
+		}
 	})
 }
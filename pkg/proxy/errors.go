@@ -0,0 +1,128 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Sentinel errors returned by Storage, use errors.Is to check
+var (
+	ErrNotFound  = errors.New("not found")
+	ErrTimeout   = errors.New("timeout")
+	ErrUnhealthy = errors.New("backend unhealthy")
+	ErrMarshal   = errors.New("marshal error")
+	ErrInvalid   = errors.New("invalid argument")
+)
+
+// Error records a failed storage operation, Kind is one of sentinel
+// errors if classified, Err is the underlying error such as grpc status
+type Error struct {
+	Op   string
+	Kind error
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Kind != nil {
+		return fmt.Sprintf("%s: %s: %s", e.Op, e.Kind, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Op, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the sentinel kind of e
+func (e *Error) Is(target error) bool {
+	return e.Kind != nil && e.Kind == target
+}
+
+// rpcError wraps error returned by proxy rpc, grpc status codes are
+// classified into sentinel errors
+func rpcError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	e := &Error{Op: op, Err: err}
+	switch status.Code(err) {
+	case codes.NotFound:
+		e.Kind = ErrNotFound
+	case codes.DeadlineExceeded:
+		e.Kind = ErrTimeout
+	case codes.Unavailable:
+		e.Kind = ErrUnhealthy
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		e.Kind = ErrTimeout
+	}
+	return e
+}
+
+// marshalError wraps bson marshal and unmarshal error
+func marshalError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Op: op, Kind: ErrMarshal, Err: err}
+}
+
+// ToStatus converts error into grpc status error with matching code,
+// services should return ToStatus(err) in grpc handlers
+//
+//     ErrNotFound  -> codes.NotFound
+//     ErrTimeout   -> codes.DeadlineExceeded
+//     ErrUnhealthy -> codes.Unavailable
+//     ErrMarshal   -> codes.InvalidArgument
+//     ErrInvalid   -> codes.InvalidArgument
+//
+// grpc status error is kept as it is, other errors are codes.Internal
+func ToStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(interface{ GRPCStatus() *status.Status }); ok {
+		return err
+	}
+	code := codes.Internal
+	switch {
+	case errors.Is(err, ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, ErrUnhealthy):
+		code = codes.Unavailable
+	case errors.Is(err, ErrMarshal), errors.Is(err, ErrInvalid):
+		code = codes.InvalidArgument
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	default:
+		var e *Error
+		if errors.As(err, &e) {
+			if s, ok := status.FromError(e.Err); ok && s.Code() != codes.OK {
+				code = s.Code()
+			}
+		}
+	}
+	return status.Error(code, err.Error())
+}
@@ -0,0 +1,51 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package proxy
+
+import (
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+// Test errors are classified and converted into grpc status codes
+func TestToStatus(t *testing.T) {
+	cases := []struct {
+		err  error
+		kind error
+		code codes.Code
+	}{
+		{rpcError(Find, status.Error(codes.NotFound, "no doc")), ErrNotFound, codes.NotFound},
+		{rpcError(Find, status.Error(codes.DeadlineExceeded, "slow")), ErrTimeout, codes.DeadlineExceeded},
+		{rpcError(Healthcheck, status.Error(codes.Unavailable, "down")), ErrUnhealthy, codes.Unavailable},
+		{marshalError(Insert, errors.New("bad doc")), ErrMarshal, codes.InvalidArgument},
+		{rpcError(Update, status.Error(codes.PermissionDenied, "auth")), nil, codes.PermissionDenied},
+		{errors.New("unknown"), nil, codes.Internal},
+	}
+	for _, c := range cases {
+		if c.kind != nil && !errors.Is(c.err, c.kind) {
+			t.Errorf("%s: expect kind %s", c.err, c.kind)
+		}
+		if code := status.Code(ToStatus(c.err)); code != c.code {
+			t.Errorf("%s: expect code %s, got %s", c.err, c.code, code)
+		}
+	}
+	if ToStatus(nil) != nil {
+		t.Error("expect nil status for nil error")
+	}
+}
@@ -21,11 +21,12 @@ import (
 	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
 )
 
-type Empty struct{}
-
 type FindAndModifyMode int
 
-// Query param for upper services
+type BulkOpType int
+
+// Query param for upper services, Profile selects consistency profile
+// of the request, storage default is used if empty
 type QueryParam struct {
 	Filter      bson.M
 	Fields      bson.M
@@ -35,28 +36,32 @@ type QueryParam struct {
 	Distinctkey string
 	FindOne     bool
 	UsingIndex  []string
+	Profile     string
 	Amp         cfg.Amplifier
 }
 
 // Insert param for upper services
 type InsertParam struct {
-	Docs []interface{}
-	Amp  cfg.Amplifier
+	Docs    []interface{}
+	Profile string
+	Amp     cfg.Amplifier
 }
 
 // Remove param for upper services
 type RemoveParam struct {
-	Filter bson.M
-	Amp    cfg.Amplifier
+	Filter  bson.M
+	Profile string
+	Amp     cfg.Amplifier
 }
 
 // Update param for upper services
 type UpdateParam struct {
-	Filter bson.M
-	Update bson.M
-	Upsert bool
-	Multi  bool
-	Amp    cfg.Amplifier
+	Filter  bson.M
+	Update  bson.M
+	Upsert  bool
+	Multi   bool
+	Profile string
+	Amp     cfg.Amplifier
 }
 
 // FindAndModify param for upper services
@@ -66,10 +71,32 @@ type FindModifyParam struct {
 	Mode     FindAndModifyMode
 	SortRule []string
 	Fields   bson.M
+	Profile  string
 	Amp      cfg.Amplifier
 }
 
 // Aggregate param for upper services
 type AggregateParam struct {
-	Pipeline bson.M
+	Pipeline []bson.M
+	Profile  string
+	Amp      cfg.Amplifier
+}
+
+// Bulk operation, Doc is inserted by BulkInsert, Filter selects documents
+// of the other types, Multi updates all matched documents
+type BulkOp struct {
+	Type   BulkOpType
+	Doc    interface{}
+	Filter bson.M
+	Update bson.M
+	Multi  bool
+}
+
+// Bulk param for upper services, ordered bulk stops at the first
+// failed operation while unordered bulk runs all of them
+type BulkParam struct {
+	Ops     []BulkOp
+	Ordered bool
+	Profile string
+	Amp     cfg.Amplifier
 }
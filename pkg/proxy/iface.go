@@ -18,11 +18,63 @@ package proxy
 
 import (
 	"context"
+	"fmt"
 	"github.com/xidongc-wish/mgo/bson"
 	"github.com/xidongc/mongo_ebenchmark/mprpc"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
 )
 
+// Storage backends
+const (
+	ProxyBackend  = "proxy"
+	MgoBackend    = "mgo"
+	MemoryBackend = "memory"
+)
+
+// Storage is the database api used by upper services, it is implemented
+// by Client through grpc proxy, by MgoStorage through mgo driver directly,
+// and by MemoryStorage in memory, so the same workload can compare latency
+// across backends
 type Storage interface {
 	Find(ctx context.Context, query *QueryParam) (docs []bson.M, err error)
 	FindIter(ctx context.Context, query *QueryParam) (stream mprpc.MongoProxy_FindIterClient, err error)
+	Count(ctx context.Context, query *QueryParam) (count uint64, err error)
+	Explain(ctx context.Context, query *QueryParam) (explainFields bson.M, err error)
+	Aggregate(ctx context.Context, query *AggregateParam) (documents []interface{}, err error)
+	Distinct(ctx context.Context, query *QueryParam) (distinctKeys []interface{}, err error)
+	Update(ctx context.Context, param *UpdateParam) (changeInfo *mprpc.ChangeInfo, err error)
+	Remove(ctx context.Context, param *RemoveParam) (changeInfo *mprpc.ChangeInfo, err error)
+	Insert(ctx context.Context, param *InsertParam) (err error)
+	FindAndModify(ctx context.Context, param *FindModifyParam) (singleDoc bson.M, err error)
+	Bulk(ctx context.Context, param *BulkParam) (result *BulkResult, err error)
+	Close() (err error)
+}
+
+// NewStorage creates a Storage based on config.Backend, proxy backend
+// is used by default
+//
+// See NewClient, NewMgoStorage and NewMemoryStorage for more details
+func NewStorage(config *cfg.ProxyConfig, namespace string, cancel context.CancelFunc) (storage Storage, err error) {
+	if config == nil {
+		config = cfg.DefaultConfig()
+	}
+	// avoid returning typed nil pointer as non-nil Storage on error
+	switch config.Backend {
+	case ProxyBackend, "":
+		client, err := NewClient(config, namespace, cancel)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	case MgoBackend:
+		mgoStorage, err := NewMgoStorage(config, namespace, cancel)
+		if err != nil {
+			return nil, err
+		}
+		return mgoStorage, nil
+	case MemoryBackend:
+		return NewMemoryStorage(config, namespace, cancel)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %s", config.Backend)
+	}
 }
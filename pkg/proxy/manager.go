@@ -0,0 +1,105 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/xidongc-wish/mgo"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+)
+
+// Manager hands out Storage of namespaces sharing connections to one
+// backend, instead of every service dialing its own:
+//
+//     proxy  - Clients share a Pool of grpc connections
+//     mgo    - storages are copies of one session, sharing its sockets
+//     memory - storages share one in-memory db already
+//
+// Call Close once storages handed out are not useful anymore
+type Manager struct {
+	config     *cfg.ProxyConfig
+	Pool       *Pool
+	session    *mgo.Session
+	cancelFunc context.CancelFunc
+}
+
+// NewManager connects to backend based on config.Backend, proxy backend
+// is used by default
+func NewManager(config *cfg.ProxyConfig, cancel context.CancelFunc) (manager *Manager, err error) {
+	if config == nil {
+		config = cfg.DefaultConfig()
+	}
+	manager = &Manager{
+		config:     config,
+		cancelFunc: cancel,
+	}
+	switch config.Backend {
+	case ProxyBackend, "":
+		manager.Pool, err = NewPool(config)
+	case MgoBackend:
+		manager.session, err = mgo.Dial(config.MongoURL)
+		if err != nil {
+			log.Errorf("connect to mongodb error: %s", err)
+			err = &Error{Op: "dial", Kind: ErrUnhealthy, Err: err}
+		}
+	case MemoryBackend:
+	default:
+		err = fmt.Errorf("unknown storage backend %s", config.Backend)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// Storage returns a Storage bound to collection namespace on shared
+// connections of manager, closing it leaves other namespaces untouched
+func (m *Manager) Storage(namespace string) (storage Storage, err error) {
+	// avoid returning typed nil pointer as non-nil Storage on error
+	switch {
+	case m.Pool != nil:
+		client, err := m.Pool.Client(namespace, nil)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	case m.session != nil:
+		mgoStorage, err := newMgoStorage(m.config, m.session.Copy(), namespace, nil)
+		if err != nil {
+			return nil, err
+		}
+		return mgoStorage, nil
+	default:
+		return NewMemoryStorage(m.config, namespace, nil)
+	}
+}
+
+// Close releases shared connections, and call cancelFunc if specified
+func (m *Manager) Close() (err error) {
+	if m.Pool != nil {
+		err = m.Pool.Close()
+	}
+	if m.session != nil {
+		m.session.Close()
+	}
+	if m.cancelFunc != nil {
+		m.cancelFunc()
+	}
+	return
+}
@@ -0,0 +1,317 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+// Package memdb is a minimal in-memory document store with mongodb
+// style filter, update and projection, it backs both the in-memory
+// storage and the fake proxy server used by tests
+package memdb
+
+import (
+	"errors"
+	"github.com/xidongc-wish/mgo/bson"
+	"sync"
+)
+
+// ErrNotFound is returned by FindAndModify if no document matched
+var ErrNotFound = errors.New("not found")
+
+// DB stores documents per namespace, DB is safe for concurrent use
+type DB struct {
+	mu          sync.RWMutex
+	collections map[string][]bson.M
+}
+
+// Query describes a find request on DB
+type Query struct {
+	Filter bson.M
+	Fields bson.M
+	Sort   []string
+	Skip   int
+	Limit  int
+}
+
+// Change describes a findAndModify request on DB, see mgo.Change
+type Change struct {
+	Update    bson.M
+	Upsert    bool
+	Remove    bool
+	ReturnNew bool
+}
+
+// New creates an empty DB
+func New() *DB {
+	return &DB{
+		collections: make(map[string][]bson.M),
+	}
+}
+
+// Drop removes all documents in all namespaces
+func (db *DB) Drop() {
+	db.mu.Lock()
+	db.collections = make(map[string][]bson.M)
+	db.mu.Unlock()
+}
+
+// Find returns copies of documents matching query
+func (db *DB) Find(ns string, query *Query) (docs []bson.M) {
+	db.mu.RLock()
+	for _, doc := range db.collections[ns] {
+		if Match(doc, query.Filter) {
+			docs = append(docs, copyDoc(doc))
+		}
+	}
+	db.mu.RUnlock()
+
+	sortDocs(docs, query.Sort)
+	if query.Skip > 0 {
+		skip := query.Skip
+		if skip > len(docs) {
+			skip = len(docs)
+		}
+		docs = docs[skip:]
+	}
+	if query.Limit > 0 && query.Limit < len(docs) {
+		docs = docs[:query.Limit]
+	}
+	for i := range docs {
+		docs[i] = Project(docs[i], query.Fields)
+	}
+	return
+}
+
+// Distinct returns distinct values of key among documents matching filter
+func (db *DB) Distinct(ns string, key string, filter bson.M) (values []interface{}) {
+	for _, doc := range db.Find(ns, &Query{Filter: filter}) {
+		val, ok := lookup(doc, key)
+		if !ok {
+			continue
+		}
+		found := false
+		for _, v := range values {
+			if equal(v, val) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			values = append(values, val)
+		}
+	}
+	return
+}
+
+// Insert stores documents, _id is generated if not specified
+func (db *DB) Insert(ns string, docs []bson.M) (inserted int) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	for _, doc := range docs {
+		doc = copyDoc(doc)
+		if _, ok := doc["_id"]; !ok {
+			doc["_id"] = bson.NewObjectId()
+		}
+		db.collections[ns] = append(db.collections[ns], doc)
+		inserted++
+	}
+	return
+}
+
+// Update modifies first or all matched documents, supports $set, $unset,
+// $inc, $push and full document replacement, inserts if upsert and no match
+func (db *DB) Update(ns string, filter bson.M, update bson.M, upsert bool, multi bool) (matched int, updated int, err error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	for i, doc := range db.collections[ns] {
+		if !Match(doc, filter) {
+			continue
+		}
+		modified, err := Apply(doc, update)
+		if err != nil {
+			return matched, updated, err
+		}
+		db.collections[ns][i] = modified
+		matched++
+		updated++
+		if !multi {
+			break
+		}
+	}
+	if matched == 0 && upsert {
+		doc, err := Upsert(filter, update)
+		if err != nil {
+			return matched, updated, err
+		}
+		db.collections[ns] = append(db.collections[ns], doc)
+		updated++
+	}
+	return
+}
+
+// Remove deletes all documents matching filter
+func (db *DB) Remove(ns string, filter bson.M) (removed int) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	var kept []bson.M
+	for _, doc := range db.collections[ns] {
+		if Match(doc, filter) {
+			removed++
+			continue
+		}
+		kept = append(kept, doc)
+	}
+	db.collections[ns] = kept
+	return
+}
+
+// FindAndModify atomically updates, upserts or removes the first document
+// matching filter in sort order, returns the old document unless ReturnNew
+// is specified, ErrNotFound is returned if nothing matched or upserted
+func (db *DB) FindAndModify(ns string, filter bson.M, sort []string, change *Change, fields bson.M) (result bson.M, err error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	index := -1
+	var candidates []bson.M
+	for _, doc := range db.collections[ns] {
+		if Match(doc, filter) {
+			candidates = append(candidates, doc)
+		}
+	}
+	if len(candidates) > 0 {
+		sortDocs(candidates, sort)
+		for i, doc := range db.collections[ns] {
+			if equal(doc["_id"], candidates[0]["_id"]) {
+				index = i
+				break
+			}
+		}
+	}
+
+	switch {
+	case index < 0 && (!change.Upsert || change.Remove):
+		return nil, ErrNotFound
+	case index < 0:
+		doc, err := Upsert(filter, change.Update)
+		if err != nil {
+			return nil, err
+		}
+		db.collections[ns] = append(db.collections[ns], doc)
+		if !change.ReturnNew {
+			return nil, nil
+		}
+		result = doc
+	case change.Remove:
+		result = db.collections[ns][index]
+		db.collections[ns] = append(db.collections[ns][:index:index], db.collections[ns][index+1:]...)
+	default:
+		old := db.collections[ns][index]
+		modified, err := Apply(old, change.Update)
+		if err != nil {
+			return nil, err
+		}
+		db.collections[ns][index] = modified
+		result = old
+		if change.ReturnNew {
+			result = modified
+		}
+	}
+	return Project(copyDoc(result), fields), nil
+}
+
+// Project applies projection to document like mongo does, fields are
+// either all included or all excluded, _id is kept unless explicitly
+// excluded
+func Project(doc bson.M, fields bson.M) bson.M {
+	if len(fields) == 0 {
+		return doc
+	}
+	inclusive, exclusive := false, false
+	for key, include := range fields {
+		if key == "_id" {
+			continue
+		}
+		if truthy(include) {
+			inclusive = true
+		} else {
+			exclusive = true
+		}
+	}
+
+	// exclusion mode, {_id: 0} alone also excludes
+	if !inclusive && (exclusive || !truthy(fields["_id"])) {
+		projected := copyDoc(doc)
+		for key := range fields {
+			unsetPath(projected, key)
+		}
+		return projected
+	}
+
+	projected := bson.M{}
+	if id, ok := doc["_id"]; ok {
+		projected["_id"] = id
+	}
+	for key, include := range fields {
+		if !truthy(include) {
+			delete(projected, key)
+			continue
+		}
+		if val, ok := lookup(doc, key); ok {
+			setPath(projected, key, copyValue(val))
+		}
+	}
+	return projected
+}
+
+// copyDoc makes a deep copy of document, stored documents are never
+// shared with callers
+func copyDoc(doc bson.M) bson.M {
+	copied := make(bson.M, len(doc))
+	for key, val := range doc {
+		copied[key] = copyValue(val)
+	}
+	return copied
+}
+
+// copyValue deep copies nested documents and arrays, other values
+// are immutable and returned as is
+func copyValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case bson.M:
+		return copyDoc(val)
+	case map[string]interface{}:
+		return map[string]interface{}(copyDoc(val))
+	case bson.D:
+		copied := make(bson.D, len(val))
+		for i, elem := range val {
+			copied[i] = bson.DocElem{Name: elem.Name, Value: copyValue(elem.Value)}
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(val))
+		for i, elem := range val {
+			copied[i] = copyValue(elem)
+		}
+		return copied
+	case []bson.M:
+		copied := make([]bson.M, len(val))
+		for i, elem := range val {
+			copied[i] = copyDoc(elem)
+		}
+		return copied
+	case []byte:
+		return append([]byte{}, val...)
+	}
+	return v
+}
@@ -0,0 +1,96 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package memdb
+
+import (
+	"github.com/xidongc-wish/mgo/bson"
+	"testing"
+)
+
+// Test conditional findAndModify only succeed once
+func TestFindAndModify(t *testing.T) {
+	db := New()
+	db.Insert("ebenchmark.order", []bson.M{{"_id": "o1", "Status": 0}})
+
+	change := &Change{
+		Update:    bson.M{"$set": bson.M{"Status": 1}},
+		ReturnNew: true,
+	}
+	result, err := db.FindAndModify("ebenchmark.order", bson.M{"_id": "o1", "Status": 0}, nil, change, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equal(result["Status"], 1) {
+		t.Errorf("expect status 1, got %v", result["Status"])
+	}
+	if _, err = db.FindAndModify("ebenchmark.order", bson.M{"_id": "o1", "Status": 0}, nil, change, nil); err != ErrNotFound {
+		t.Errorf("expect not found on second transition, got %v", err)
+	}
+
+	removed, err := db.FindAndModify("ebenchmark.order", bson.M{"_id": "o1"}, nil, &Change{Remove: true}, bson.M{"Status": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 2 || len(db.Find("ebenchmark.order", &Query{})) != 0 {
+		t.Errorf("unexpected remove result %v", removed)
+	}
+}
+
+// Test inclusion and exclusion projections
+func TestProject(t *testing.T) {
+	doc := bson.M{"_id": "p1", "name": "mavic", "price": 100, "meta": bson.M{"color": "grey", "size": 2}}
+
+	projected := Project(doc, bson.M{"name": 1, "meta.color": 1})
+	if len(projected) != 3 || projected["name"] != "mavic" || len(projected["meta"].(bson.M)) != 1 {
+		t.Errorf("unexpected inclusion projection %v", projected)
+	}
+	projected = Project(doc, bson.M{"name": 1, "_id": 0})
+	if len(projected) != 1 || projected["name"] != "mavic" {
+		t.Errorf("unexpected inclusion projection without _id %v", projected)
+	}
+	projected = Project(doc, bson.M{"price": 0, "meta.size": 0})
+	if len(projected) != 3 || projected["_id"] != "p1" || len(projected["meta"].(bson.M)) != 1 {
+		t.Errorf("unexpected exclusion projection %v", projected)
+	}
+	projected = Project(doc, bson.M{"_id": 0})
+	if len(projected) != 3 || projected["_id"] != nil {
+		t.Errorf("unexpected _id exclusion projection %v", projected)
+	}
+	if len(doc) != 4 || len(doc["meta"].(bson.M)) != 2 {
+		t.Errorf("expect projection leave document untouched, got %v", doc)
+	}
+}
+
+// Test stored documents are not shared with callers
+func TestDeepCopy(t *testing.T) {
+	db := New()
+	doc := bson.M{"_id": "p1", "meta": bson.M{"color": "grey"}, "tags": []interface{}{"drone"}}
+	db.Insert("ebenchmark.product", []bson.M{doc})
+	doc["meta"].(bson.M)["color"] = "white"
+
+	found := db.Find("ebenchmark.product", &Query{})
+	if len(found) != 1 || found[0]["meta"].(bson.M)["color"] != "grey" {
+		t.Fatalf("expect inserted document copied, got %v", found)
+	}
+	found[0]["meta"].(bson.M)["color"] = "black"
+	found[0]["tags"].([]interface{})[0] = "camera"
+
+	found = db.Find("ebenchmark.product", &Query{})
+	if found[0]["meta"].(bson.M)["color"] != "grey" || found[0]["tags"].([]interface{})[0] != "drone" {
+		t.Errorf("expect found document copied, got %v", found)
+	}
+}
@@ -0,0 +1,476 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package memdb
+
+import (
+	"fmt"
+	"github.com/xidongc-wish/mgo/bson"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Match reports whether document matches given filter, a subset of
+// mongodb query language is supported:
+//
+//     equality, $eq, $ne, $gt, $gte, $lt, $lte, $in, $nin, $exists,
+//     $and, $or, $nor and dotted path for nested document
+//
+// Relevant documentation:
+//
+//     https://docs.mongodb.com/manual/reference/operator/query/
+//
+func Match(doc bson.M, filter bson.M) bool {
+	for key, cond := range filter {
+		switch key {
+		case "$and":
+			for _, sub := range toDocs(cond) {
+				if !Match(doc, sub) {
+					return false
+				}
+			}
+		case "$or":
+			matched := false
+			for _, sub := range toDocs(cond) {
+				if Match(doc, sub) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return false
+			}
+		case "$nor":
+			for _, sub := range toDocs(cond) {
+				if Match(doc, sub) {
+					return false
+				}
+			}
+		default:
+			val, exists := lookup(doc, key)
+			if !matchCond(val, exists, cond) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matchCond matches a single field value against condition, condition
+// is either a literal value or a document of operators
+func matchCond(val interface{}, exists bool, cond interface{}) bool {
+	ops, ok := toDoc(cond)
+	if !ok || !isOperatorDoc(ops) {
+		return exists && matchValue(val, cond)
+	}
+	for op, arg := range ops {
+		switch op {
+		case "$eq":
+			if !exists || !matchValue(val, arg) {
+				return false
+			}
+		case "$ne":
+			if exists && matchValue(val, arg) {
+				return false
+			}
+		case "$gt", "$gte", "$lt", "$lte":
+			if !exists {
+				return false
+			}
+			c, ok := compare(val, arg)
+			if !ok {
+				return false
+			}
+			if (op == "$gt" && c <= 0) || (op == "$gte" && c < 0) ||
+				(op == "$lt" && c >= 0) || (op == "$lte" && c > 0) {
+				return false
+			}
+		case "$in":
+			if !exists || !inSlice(val, arg) {
+				return false
+			}
+		case "$nin":
+			if exists && inSlice(val, arg) {
+				return false
+			}
+		case "$exists":
+			if exists != truthy(arg) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// matchValue matches equality, array field matches if any element equals
+func matchValue(val interface{}, expected interface{}) bool {
+	if equal(val, expected) {
+		return true
+	}
+	if arr, ok := val.([]interface{}); ok {
+		for _, elem := range arr {
+			if equal(elem, expected) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inSlice reports whether val equals any element of arg
+func inSlice(val interface{}, arg interface{}) bool {
+	rv := reflect.ValueOf(arg)
+	if rv.Kind() != reflect.Slice {
+		return false
+	}
+	for i := 0; i < rv.Len(); i++ {
+		if matchValue(val, rv.Index(i).Interface()) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup finds value by dotted path, eg: Inventory.Quantity, numeric
+// part indexes into array, eg: Inventory.0.Quantity
+func lookup(doc bson.M, path string) (val interface{}, ok bool) {
+	var current interface{} = doc
+	for _, part := range strings.Split(path, ".") {
+		if arr, isArr := current.([]interface{}); isArr {
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(arr) {
+				return nil, false
+			}
+			current = arr[i]
+			continue
+		}
+		sub, isDoc := toDoc(current)
+		if !isDoc {
+			return nil, false
+		}
+		if current, ok = sub[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// equal compares two bson values, numbers are compared by value
+// regardless of int32, int64 or float64 encoding
+func equal(a interface{}, b interface{}) bool {
+	if c, ok := compare(a, b); ok {
+		return c == 0
+	}
+	aDoc, aIsDoc := toDoc(a)
+	bDoc, bIsDoc := toDoc(b)
+	if aIsDoc && bIsDoc {
+		if len(aDoc) != len(bDoc) {
+			return false
+		}
+		for key, val := range aDoc {
+			if !equal(val, bDoc[key]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// compare orders numbers, strings and times, ok is false if a and b
+// are not comparable
+func compare(a interface{}, b interface{}) (c int, ok bool) {
+	if af, aok := toFloat(a); aok {
+		if bf, bok := toFloat(b); bok {
+			switch {
+			case af < bf:
+				return -1, true
+			case af > bf:
+				return 1, true
+			}
+			return 0, true
+		}
+		return 0, false
+	}
+	switch av := a.(type) {
+	case string:
+		if bv, bok := b.(string); bok {
+			return strings.Compare(av, bv), true
+		}
+	case bool:
+		if bv, bok := b.(bool); bok {
+			if av == bv {
+				return 0, true
+			}
+			if !av {
+				return -1, true
+			}
+			return 1, true
+		}
+	case time.Time:
+		if bv, bok := b.(time.Time); bok {
+			switch {
+			case av.Before(bv):
+				return -1, true
+			case av.After(bv):
+				return 1, true
+			}
+			return 0, true
+		}
+	case bson.ObjectId:
+		if bv, bok := b.(bson.ObjectId); bok {
+			return strings.Compare(string(av), string(bv)), true
+		}
+	}
+	return 0, false
+}
+
+// toFloat converts any numeric value into float64
+func toFloat(v interface{}) (f float64, ok bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// truthy follows mongodb semantic for flags such as $exists and projection
+func truthy(v interface{}) bool {
+	if b, ok := v.(bool); ok {
+		return b
+	}
+	if f, ok := toFloat(v); ok {
+		return f != 0
+	}
+	return v != nil
+}
+
+// toDoc converts bson.M, bson.D or map[string]interface{} into bson.M
+func toDoc(v interface{}) (doc bson.M, ok bool) {
+	switch d := v.(type) {
+	case bson.M:
+		return d, true
+	case map[string]interface{}:
+		return d, true
+	case bson.D:
+		return d.Map(), true
+	}
+	return nil, false
+}
+
+// toDocs converts array of documents used by $and, $or, $nor
+func toDocs(v interface{}) (docs []bson.M) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return
+	}
+	for i := 0; i < rv.Len(); i++ {
+		if doc, ok := toDoc(rv.Index(i).Interface()); ok {
+			docs = append(docs, doc)
+		}
+	}
+	return
+}
+
+// isOperatorDoc reports whether all keys of doc are $ operators
+func isOperatorDoc(doc bson.M) bool {
+	if len(doc) == 0 {
+		return false
+	}
+	for key := range doc {
+		if !strings.HasPrefix(key, "$") {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns a new document with update applied, update without
+// operators replaces the whole document except _id
+func Apply(doc bson.M, update bson.M) (modified bson.M, err error) {
+	if !isOperatorDoc(update) {
+		modified = copyDoc(update)
+		if id, ok := doc["_id"]; ok {
+			modified["_id"] = id
+		}
+		return
+	}
+	modified = copyDoc(doc)
+	for op, arg := range update {
+		fields, ok := toDoc(arg)
+		if !ok {
+			return nil, fmt.Errorf("invalid argument for %s", op)
+		}
+		for path, val := range fields {
+			switch op {
+			case "$set":
+				setPath(modified, path, val)
+			case "$setOnInsert":
+				// only applied by upsert
+			case "$unset":
+				unsetPath(modified, path)
+			case "$inc":
+				current, _ := lookup(modified, path)
+				sum, err := add(current, val)
+				if err != nil {
+					return nil, err
+				}
+				setPath(modified, path, sum)
+			case "$push":
+				current, _ := lookup(modified, path)
+				arr, _ := current.([]interface{})
+				setPath(modified, path, append(append([]interface{}{}, arr...), val))
+			default:
+				return nil, fmt.Errorf("unsupported update operator %s", op)
+			}
+		}
+	}
+	return
+}
+
+// Upsert builds a new document from equality conditions in filter
+// and update, _id is generated if not specified
+func Upsert(filter bson.M, update bson.M) (doc bson.M, err error) {
+	doc = bson.M{}
+	if isOperatorDoc(update) {
+		for key, cond := range filter {
+			if strings.HasPrefix(key, "$") {
+				continue
+			}
+			if ops, ok := toDoc(cond); ok && isOperatorDoc(ops) {
+				if eq, ok := ops["$eq"]; ok {
+					setPath(doc, key, eq)
+				}
+				continue
+			}
+			setPath(doc, key, cond)
+		}
+		if onInsert, ok := toDoc(update["$setOnInsert"]); ok {
+			for path, val := range onInsert {
+				setPath(doc, path, val)
+			}
+		}
+	}
+	if doc, err = Apply(doc, update); err != nil {
+		return
+	}
+	if _, ok := doc["_id"]; !ok {
+		if id, ok := filter["_id"]; ok {
+			doc["_id"] = id
+		} else {
+			doc["_id"] = bson.NewObjectId()
+		}
+	}
+	return
+}
+
+// add sums two numbers keeping integer type when possible
+func add(a interface{}, b interface{}) (interface{}, error) {
+	if a == nil {
+		return b, nil
+	}
+	af, aok := toFloat(a)
+	bf, bok := toFloat(b)
+	if !aok || !bok {
+		return nil, fmt.Errorf("cannot apply $inc to non-numeric value")
+	}
+	_, aFloat := a.(float64)
+	_, bFloat := b.(float64)
+	if aFloat || bFloat {
+		return af + bf, nil
+	}
+	return int64(af + bf), nil
+}
+
+// setPath sets value by dotted path, intermediate documents are created,
+// numeric part indexes into existing array element
+func setPath(doc bson.M, path string, val interface{}) {
+	parts := strings.Split(path, ".")
+	doc[parts[0]] = setIn(doc[parts[0]], parts[1:], val)
+}
+
+// setIn returns a copy of current with value set at path parts
+func setIn(current interface{}, parts []string, val interface{}) interface{} {
+	if len(parts) == 0 {
+		return val
+	}
+	if arr, ok := current.([]interface{}); ok {
+		if i, err := strconv.Atoi(parts[0]); err == nil && i >= 0 && i < len(arr) {
+			arr = append([]interface{}{}, arr...)
+			arr[i] = setIn(arr[i], parts[1:], val)
+			return arr
+		}
+	}
+	sub, ok := toDoc(current)
+	if !ok {
+		sub = bson.M{}
+	} else {
+		sub = copyDoc(sub)
+	}
+	sub[parts[0]] = setIn(sub[parts[0]], parts[1:], val)
+	return sub
+}
+
+// unsetPath removes value by dotted path
+func unsetPath(doc bson.M, path string) {
+	parts := strings.Split(path, ".")
+	current := doc
+	for _, part := range parts[:len(parts)-1] {
+		sub, ok := toDoc(current[part])
+		if !ok {
+			return
+		}
+		sub = copyDoc(sub)
+		current[part] = sub
+		current = sub
+	}
+	delete(current, parts[len(parts)-1])
+}
+
+// sortDocs sorts documents by mgo style sort keys, a "-" prefix
+// means descending order
+func sortDocs(docs []bson.M, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	sort.SliceStable(docs, func(i, j int) bool {
+		for _, key := range keys {
+			desc := strings.HasPrefix(key, "-")
+			field := strings.TrimPrefix(strings.TrimPrefix(key, "-"), "+")
+			a, _ := lookup(docs[i], field)
+			b, _ := lookup(docs[j], field)
+			c, ok := compare(a, b)
+			if !ok || c == 0 {
+				continue
+			}
+			if desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+}
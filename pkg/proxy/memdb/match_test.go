@@ -0,0 +1,103 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package memdb
+
+import (
+	"github.com/xidongc-wish/mgo/bson"
+	"testing"
+)
+
+// Test filter matching on a sku like document
+func TestMatch(t *testing.T) {
+	doc := bson.M{
+		"Name":       "mavic pro",
+		"Price":      int64(1000),
+		"Active":     true,
+		"Attributes": []interface{}{"drone", "dji"},
+		"PackageDimensions": bson.M{
+			"Weight": 10.3,
+		},
+	}
+
+	cases := []struct {
+		name   string
+		filter bson.M
+		want   bool
+	}{
+		{"empty", bson.M{}, true},
+		{"equal", bson.M{"Name": "mavic pro"}, true},
+		{"not equal", bson.M{"Name": "mavic air"}, false},
+		{"numeric cross type", bson.M{"Price": 1000}, true},
+		{"gte", bson.M{"Price": bson.M{"$gte": 1000}}, true},
+		{"lt", bson.M{"Price": bson.M{"$lt": 1000}}, false},
+		{"in", bson.M{"Name": bson.M{"$in": []interface{}{"mavic air", "mavic pro"}}}, true},
+		{"nin", bson.M{"Name": bson.M{"$nin": []string{"mavic pro"}}}, false},
+		{"array element", bson.M{"Attributes": "dji"}, true},
+		{"dotted path", bson.M{"PackageDimensions.Weight": bson.M{"$gt": 10}}, true},
+		{"exists", bson.M{"Supplier": bson.M{"$exists": false}}, true},
+		{"ne missing", bson.M{"Supplier": bson.M{"$ne": "dji"}}, true},
+		{"or", bson.M{"$or": []interface{}{bson.M{"Active": false}, bson.M{"Price": 1000}}}, true},
+		{"and", bson.M{"$and": []bson.M{{"Active": true}, {"Price": 999}}}, false},
+	}
+	for _, c := range cases {
+		if got := Match(doc, c.filter); got != c.want {
+			t.Errorf("%s: expect %v, got %v", c.name, c.want, got)
+		}
+	}
+}
+
+// Test update operators and replacement
+func TestApply(t *testing.T) {
+	doc := bson.M{"_id": "id", "Quantity": 10, "Name": "sku"}
+
+	modified, err := Apply(doc, bson.M{"$inc": bson.M{"Quantity": -3}, "$set": bson.M{"Inventory.Type": 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equal(modified["Quantity"], 7) {
+		t.Errorf("expect quantity 7, got %v", modified["Quantity"])
+	}
+	if val, ok := lookup(modified, "Inventory.Type"); !ok || !equal(val, 1) {
+		t.Errorf("expect nested field set, got %v", modified)
+	}
+	if !equal(doc["Quantity"], 10) {
+		t.Error("original document should not be modified")
+	}
+
+	stock := bson.M{"Inventory": []interface{}{bson.M{"Quantity": 1}, bson.M{"Quantity": 5}}}
+	if !Match(stock, bson.M{"Inventory.1.Quantity": bson.M{"$gte": 3}}) || Match(stock, bson.M{"Inventory.0.Quantity": bson.M{"$gte": 3}}) {
+		t.Error("array index in path should match indexed element")
+	}
+	decremented, err := Apply(stock, bson.M{"$inc": bson.M{"Inventory.1.Quantity": -3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val, ok := lookup(decremented, "Inventory.1.Quantity"); !ok || !equal(val, 2) {
+		t.Errorf("expect indexed element decremented, got %v", decremented)
+	}
+	if val, _ := lookup(stock, "Inventory.1.Quantity"); !equal(val, 5) {
+		t.Error("original array should not be modified")
+	}
+
+	replaced, err := Apply(doc, bson.M{"Name": "new"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replaced["_id"] != "id" || replaced["Name"] != "new" || replaced["Quantity"] != nil {
+		t.Errorf("unexpected replacement result %v", replaced)
+	}
+}
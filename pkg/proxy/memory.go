@@ -0,0 +1,209 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/xidongc-wish/mgo/bson"
+	"github.com/xidongc/mongo_ebenchmark/mprpc"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"github.com/xidongc/mongo_ebenchmark/pkg/proxy/memdb"
+)
+
+// MemoryStorage is a Storage keeping documents in process memory,
+// it is useful as a baseline to measure service overhead without
+// any network or database cost
+//
+// amplification is not supported, Amp in params is ignored
+type MemoryStorage struct {
+	config     *cfg.ProxyConfig
+	namespace  string
+	db         *memdb.DB
+	cancelFunc context.CancelFunc
+}
+
+// sharedMemDB is shared by all memory storage in process, so services
+// with different namespace see each other's documents like a database
+var sharedMemDB = memdb.New()
+
+// NewMemoryStorage creates a memory storage on given namespace
+//
+// Call Close to release resources once storage is not useful anymore
+func NewMemoryStorage(config *cfg.ProxyConfig, namespace string, cancel context.CancelFunc) (storage *MemoryStorage, err error) {
+	if config == nil {
+		config = cfg.DefaultConfig()
+	}
+	if namespace == "" {
+		namespace = Collection
+	}
+	storage = &MemoryStorage{
+		config:     config,
+		namespace:  fmt.Sprintf("%s.%s", Database, namespace),
+		db:         sharedMemDB,
+		cancelFunc: cancel,
+	}
+	return
+}
+
+// Close calls cancelFunc if specified
+func (m *MemoryStorage) Close() (err error) {
+	if m.cancelFunc != nil {
+		m.cancelFunc()
+	}
+	return
+}
+
+// Find returns documents matching query
+func (m *MemoryStorage) Find(ctx context.Context, query *QueryParam) (docs []bson.M, err error) {
+	return m.db.Find(m.namespace, m.query(query)), nil
+}
+
+// FindIter works like Find, documents are returned in batches of BatchSize
+func (m *MemoryStorage) FindIter(ctx context.Context, query *QueryParam) (stream mprpc.MongoProxy_FindIterClient, err error) {
+	docs := m.db.Find(m.namespace, m.query(query))
+	return sliceStream(docs, m.config.BatchSize), nil
+}
+
+// Count returns number of documents matching query
+func (m *MemoryStorage) Count(ctx context.Context, query *QueryParam) (count uint64, err error) {
+	return uint64(len(m.db.Find(m.namespace, m.query(query)))), nil
+}
+
+// Explain returns a collection scan plan, since memory storage has no index
+func (m *MemoryStorage) Explain(ctx context.Context, query *QueryParam) (explainFields bson.M, err error) {
+	docs := m.db.Find(m.namespace, m.query(query))
+	explainFields = bson.M{
+		"queryPlanner": bson.M{
+			"namespace":   m.namespace,
+			"winningPlan": bson.M{"stage": "COLLSCAN"},
+		},
+		"executionStats": bson.M{
+			"nReturned": len(docs),
+		},
+	}
+	return
+}
+
+// Aggregate is not supported by memory storage
+func (m *MemoryStorage) Aggregate(ctx context.Context, query *AggregateParam) (documents []interface{}, err error) {
+	return nil, errors.New("aggregate not supported by memory storage")
+}
+
+// Distinct returns distinct values of Distinctkey
+func (m *MemoryStorage) Distinct(ctx context.Context, query *QueryParam) (distinctKeys []interface{}, err error) {
+	if query.Distinctkey == "" {
+		return nil, &Error{Op: Distinct, Kind: ErrMarshal, Err: errors.New("distinct key not specified")}
+	}
+	return m.db.Distinct(m.namespace, query.Distinctkey, query.Filter), nil
+}
+
+// Update modifies documents matching filter
+func (m *MemoryStorage) Update(ctx context.Context, param *UpdateParam) (changeInfo *mprpc.ChangeInfo, err error) {
+	update, err := toBsonM(param.Update)
+	if err != nil {
+		return nil, marshalError(Update, err)
+	}
+	matched, updated, err := m.db.Update(m.namespace, param.Filter, update, param.Upsert, param.Multi)
+	if err != nil {
+		log.Error(err)
+		return nil, &Error{Op: Update, Err: err}
+	}
+	changeInfo = &mprpc.ChangeInfo{
+		Matched: int64(matched),
+		Updated: int64(updated),
+	}
+	return
+}
+
+// Remove deletes documents matching filter
+func (m *MemoryStorage) Remove(ctx context.Context, param *RemoveParam) (changeInfo *mprpc.ChangeInfo, err error) {
+	changeInfo = &mprpc.ChangeInfo{
+		Removed: int64(m.db.Remove(m.namespace, param.Filter)),
+	}
+	return
+}
+
+// Insert stores documents, structs are converted with bson tags
+func (m *MemoryStorage) Insert(ctx context.Context, param *InsertParam) (err error) {
+	var docs []bson.M
+	for _, doc := range param.Docs {
+		converted, err := toBsonM(doc)
+		if err != nil {
+			log.Errorf("unable to marshall error: %s", err)
+			return marshalError(Insert, err)
+		}
+		docs = append(docs, converted)
+	}
+	m.db.Insert(m.namespace, docs)
+	return
+}
+
+// FindAndModify atomically modifies and returns a single document
+func (m *MemoryStorage) FindAndModify(ctx context.Context, param *FindModifyParam) (singleDoc bson.M, err error) {
+	var update bson.M
+	if param.Mode != FindAndDelete {
+		if update, err = toBsonM(param.Desired); err != nil {
+			return nil, marshalError(FindAndModify, err)
+		}
+	}
+	change := &memdb.Change{
+		Update:    update,
+		Upsert:    param.Mode == FindAndUpsert,
+		Remove:    param.Mode == FindAndDelete,
+		ReturnNew: param.Mode != FindAndDelete,
+	}
+	singleDoc, err = m.db.FindAndModify(m.namespace, param.Filter, param.SortRule, change, param.Fields)
+	if err == memdb.ErrNotFound {
+		return nil, &Error{Op: FindAndModify, Kind: ErrNotFound, Err: err}
+	}
+	return
+}
+
+// Bulk runs operations one by one, it is not atomic as a whole
+func (m *MemoryStorage) Bulk(ctx context.Context, param *BulkParam) (result *BulkResult, err error) {
+	return runBulk(ctx, m, param)
+}
+
+// query converts QueryParam into memdb.Query
+func (m *MemoryStorage) query(query *QueryParam) *memdb.Query {
+	limit := int(query.Limit)
+	if query.FindOne {
+		limit = 1
+	}
+	return &memdb.Query{
+		Filter: query.Filter,
+		Fields: query.Fields,
+		Sort:   query.Sort,
+		Skip:   int(query.Skip),
+		Limit:  limit,
+	}
+}
+
+// toBsonM converts a document into bson.M through bson marshal, so
+// documents, including structs nested in bson.M, are stored the same
+// way as through proxy
+func toBsonM(doc interface{}) (converted bson.M, err error) {
+	b, err := bson.Marshal(doc)
+	if err != nil {
+		return
+	}
+	err = bson.Unmarshal(b, &converted)
+	return
+}
@@ -0,0 +1,105 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package proxy
+
+import (
+	"context"
+	"github.com/xidongc-wish/mgo/bson"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"testing"
+)
+
+// Test memory storage behaves as a Storage backend
+func TestMemoryStorage(t *testing.T) {
+	config := cfg.DefaultConfig()
+	config.Backend = MemoryBackend
+	config.BatchSize = 1
+
+	storage, err := NewStorage(config, "memory_test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := storage.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	ctx := context.Background()
+
+	docs := []interface{}{
+		bson.M{"name": "mavic", "price": 1000},
+		bson.M{"name": "care", "price": 75},
+	}
+	if err := storage.Insert(ctx, &InsertParam{Docs: docs}); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := storage.Find(ctx, &QueryParam{Filter: bson.M{"price": bson.M{"$gt": 100}}})
+	if err != nil || len(results) != 1 || results[0]["name"] != "mavic" {
+		t.Errorf("unexpected find result %v, %v", results, err)
+	}
+
+	stream, err := storage.FindIter(ctx, &QueryParam{Filter: bson.M{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	batches := 0
+	for {
+		if _, err := stream.Recv(); err != nil {
+			break
+		}
+		batches++
+	}
+	if batches != 2 {
+		t.Errorf("expect 2 batches, got %d", batches)
+	}
+
+	doc, err := storage.FindAndModify(ctx, &FindModifyParam{
+		Filter:  bson.M{"name": "care"},
+		Desired: bson.M{"$inc": bson.M{"price": 5}},
+		Mode:    FindAndUpdate,
+	})
+	if err != nil || !(doc["price"] == int64(80)) {
+		t.Errorf("unexpected findAndModify result %v, %v", doc, err)
+	}
+
+	changeInfo, err := storage.Remove(ctx, &RemoveParam{Filter: bson.M{}})
+	if err != nil || changeInfo.Removed != 2 {
+		t.Errorf("unexpected remove result %v, %v", changeInfo, err)
+	}
+}
+
+// Test memory storage bulk runs operations one by one
+func TestMemoryBulk(t *testing.T) {
+	config := cfg.DefaultConfig()
+	config.Backend = MemoryBackend
+	storage, _ := NewStorage(config, "memory_bulk_test", nil)
+	ctx := context.Background()
+
+	result, err := storage.Bulk(ctx, &BulkParam{Ops: []BulkOp{
+		{Type: BulkInsert, Doc: bson.M{"name": "mavic"}},
+		{Type: BulkUpsert, Filter: bson.M{"name": "care"}, Update: bson.M{"$set": bson.M{"price": 75}}},
+		{Type: BulkUpdate, Filter: bson.M{}, Update: bson.M{"$set": bson.M{"active": true}}, Multi: true},
+		{Type: BulkRemove, Filter: bson.M{"name": "mavic"}},
+	}})
+	if err != nil || result.Inserted != 1 || result.Updated != 3 || result.Removed != 1 {
+		t.Errorf("unexpected bulk result %+v, %v", result, err)
+	}
+	if count, _ := storage.Count(ctx, &QueryParam{Filter: bson.M{"active": true}}); count != 1 {
+		t.Errorf("expect 1 document left, got %d", count)
+	}
+}
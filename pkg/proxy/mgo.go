@@ -0,0 +1,429 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package proxy
+
+import (
+	"context"
+	"errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/xidongc-wish/mgo"
+	"github.com/xidongc-wish/mgo/bson"
+	"github.com/xidongc/mongo_ebenchmark/mprpc"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"io"
+	"net"
+	"time"
+)
+
+// MgoStorage is a Storage talking to mongodb through mgo driver directly,
+// it skips grpc proxy, and is used to compare "through proxy" latency
+// against "direct driver" latency under the same workload
+//
+// amplification is done by ghz against proxy, so Amp in params is ignored
+type MgoStorage struct {
+	config     *cfg.ProxyConfig
+	session    *mgo.Session
+	namespace  string
+	profile    *cfg.Profile
+	cancelFunc context.CancelFunc
+}
+
+// NewMgoStorage dials mongodb at config.MongoURL, session mode and
+// write safety follow consistency profile, same as Client
+//
+// Call Close to release the session once storage is not useful anymore
+func NewMgoStorage(config *cfg.ProxyConfig, namespace string, cancel context.CancelFunc) (storage *MgoStorage, err error) {
+	if config == nil {
+		config = cfg.DefaultConfig()
+	}
+	session, err := mgo.Dial(config.MongoURL)
+	if err != nil {
+		log.Errorf("connect to mongodb error: %s", err)
+		return nil, &Error{Op: "dial", Kind: ErrUnhealthy, Err: err}
+	}
+	storage, err = newMgoStorage(config, session, namespace, cancel)
+	if err != nil {
+		session.Close()
+	}
+	return
+}
+
+// newMgoStorage binds session to namespace, session is owned by
+// storage and closed by Close
+func newMgoStorage(config *cfg.ProxyConfig, session *mgo.Session, namespace string, cancel context.CancelFunc) (storage *MgoStorage, err error) {
+	if namespace == "" {
+		namespace = Collection
+	}
+	profile, err := config.Profile(namespace)
+	if err != nil {
+		return nil, &Error{Op: "dial", Kind: ErrInvalid, Err: err}
+	}
+	applyProfile(session, profile)
+	session.SetSocketTimeout(time.Duration(config.RpcTimeout) * time.Millisecond)
+
+	storage = &MgoStorage{
+		config:     config,
+		session:    session,
+		namespace:  namespace,
+		profile:    profile,
+		cancelFunc: cancel,
+	}
+	return
+}
+
+// Close releases mgo session, and call cancelFunc if specified
+func (m *MgoStorage) Close() (err error) {
+	m.session.Close()
+	if m.cancelFunc != nil {
+		m.cancelFunc()
+	}
+	return
+}
+
+// HealthCheck pings mongodb on a copy of session
+func (m *MgoStorage) HealthCheck(ctx context.Context) error {
+	session := m.session.Copy()
+	defer session.Close()
+	return mgoError(Healthcheck, session.Ping())
+}
+
+// Find prepares a query using the provided document
+func (m *MgoStorage) Find(ctx context.Context, query *QueryParam) (docs []bson.M, err error) {
+	session, q, err := m.query(ctx, Find, query)
+	if err != nil {
+		return
+	}
+	defer session.Close()
+	if query.FindOne {
+		var doc bson.M
+		if err = q.One(&doc); err == mgo.ErrNotFound {
+			return nil, nil
+		} else if err != nil {
+			return nil, mgoError(Find, err)
+		}
+		return []bson.M{doc}, nil
+	}
+	err = mgoError(Find, q.All(&docs))
+	return
+}
+
+// FindIter works like Find, but iterates documents with mgo cursor
+func (m *MgoStorage) FindIter(ctx context.Context, query *QueryParam) (stream mprpc.MongoProxy_FindIterClient, err error) {
+	session, q, err := m.query(ctx, FindIter, query)
+	if err != nil {
+		return
+	}
+	iter := q.Iter()
+	return &docStream{
+		next: func() (doc bson.M, ok bool, err error) {
+			if iter.Next(&doc) {
+				return doc, true, nil
+			}
+			err = iter.Close()
+			session.Close()
+			return nil, false, err
+		},
+		batchSize: int(m.profile.BatchSize),
+	}, nil
+}
+
+// Count returns number of documents matching query
+func (m *MgoStorage) Count(ctx context.Context, query *QueryParam) (count uint64, err error) {
+	session, q, err := m.query(ctx, Count, query)
+	if err != nil {
+		return
+	}
+	defer session.Close()
+	n, err := q.Count()
+	count = uint64(n)
+	err = mgoError(Count, err)
+	return
+}
+
+// Explain returns query plan of given query
+func (m *MgoStorage) Explain(ctx context.Context, query *QueryParam) (explainFields bson.M, err error) {
+	session, q, err := m.query(ctx, Explain, query)
+	if err != nil {
+		return
+	}
+	defer session.Close()
+	err = mgoError(Explain, q.Explain(&explainFields))
+	return
+}
+
+// Aggregate runs aggregation pipeline on collection
+func (m *MgoStorage) Aggregate(ctx context.Context, query *AggregateParam) (documents []interface{}, err error) {
+	session, err := m.copySession(ctx, Aggregate, query.Profile)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+	var results []bson.M
+	if err = m.collection(session).Pipe(query.Pipeline).All(&results); err != nil {
+		return nil, mgoError(Aggregate, err)
+	}
+	for _, result := range results {
+		documents = append(documents, result)
+	}
+	return
+}
+
+// Distinct returns distinct values of Distinctkey
+func (m *MgoStorage) Distinct(ctx context.Context, query *QueryParam) (distinctKeys []interface{}, err error) {
+	if query.Distinctkey == "" {
+		return nil, &Error{Op: Distinct, Kind: ErrMarshal, Err: errors.New("distinct key not specified")}
+	}
+	session, q, err := m.query(ctx, Distinct, query)
+	if err != nil {
+		return
+	}
+	defer session.Close()
+	err = mgoError(Distinct, q.Distinct(query.Distinctkey, &distinctKeys))
+	return
+}
+
+// Update modifies single or all documents matching filter
+func (m *MgoStorage) Update(ctx context.Context, param *UpdateParam) (changeInfo *mprpc.ChangeInfo, err error) {
+	session, err := m.copySession(ctx, Update, param.Profile)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+	c := m.collection(session)
+
+	var info *mgo.ChangeInfo
+	switch {
+	case param.Upsert:
+		info, err = c.Upsert(param.Filter, param.Update)
+	case param.Multi:
+		info, err = c.UpdateAll(param.Filter, param.Update)
+	default:
+		if info, err = c.Update(param.Filter, param.Update); err == mgo.ErrNotFound {
+			info, err = &mgo.ChangeInfo{}, nil
+		}
+	}
+	if err != nil {
+		log.Error(err)
+		return nil, mgoError(Update, err)
+	}
+	return toChangeInfo(info), nil
+}
+
+// Remove deletes all documents matching filter
+func (m *MgoStorage) Remove(ctx context.Context, param *RemoveParam) (changeInfo *mprpc.ChangeInfo, err error) {
+	session, err := m.copySession(ctx, Remove, param.Profile)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+	info, err := m.collection(session).RemoveAll(param.Filter)
+	if err != nil {
+		log.Error(err)
+		return nil, mgoError(Remove, err)
+	}
+	return toChangeInfo(info), nil
+}
+
+// Insert inserts documents in one batch
+func (m *MgoStorage) Insert(ctx context.Context, param *InsertParam) (err error) {
+	session, err := m.copySession(ctx, Insert, param.Profile)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+	if err = m.collection(session).Insert(param.Docs...); err != nil {
+		log.Errorf("mgo insert error with: %s", err)
+		return mgoError(Insert, err)
+	}
+	return
+}
+
+// FindAndModify atomically modifies and returns a single document
+func (m *MgoStorage) FindAndModify(ctx context.Context, param *FindModifyParam) (singleDoc bson.M, err error) {
+	session, err := m.copySession(ctx, FindAndModify, param.Profile)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+	q := m.collection(session).Find(param.Filter)
+	if len(param.SortRule) > 0 {
+		q = q.Sort(param.SortRule...)
+	}
+	if param.Fields != nil {
+		q = q.Select(param.Fields)
+	}
+	change := mgo.Change{
+		Update:    param.Desired,
+		Upsert:    param.Mode == FindAndUpsert,
+		Remove:    param.Mode == FindAndDelete,
+		ReturnNew: param.Mode != FindAndDelete,
+	}
+	_, err = q.Apply(change, &singleDoc)
+	return singleDoc, mgoError(FindAndModify, err)
+}
+
+// Bulk runs operations with mgo bulk api in chunks of batch size, mgo
+// reports matched and modified totals only, so ChangeInfo of results is nil
+func (m *MgoStorage) Bulk(ctx context.Context, param *BulkParam) (result *BulkResult, err error) {
+	if err = validateBulk(param); err != nil {
+		return
+	}
+	session, err := m.copySession(ctx, Bulk, param.Profile)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+	c := m.collection(session)
+
+	result = &BulkResult{}
+	for _, chunk := range bulkChunks(len(param.Ops), m.profile.BatchSize) {
+		start, end := chunk[0], chunk[1]
+		bulk := c.Bulk()
+		if !param.Ordered {
+			bulk.Unordered()
+		}
+		for _, op := range param.Ops[start:end] {
+			switch {
+			case op.Type == BulkInsert:
+				bulk.Insert(op.Doc)
+			case op.Type == BulkUpsert:
+				bulk.Upsert(op.Filter, op.Update)
+			case op.Type == BulkUpdate && op.Multi:
+				bulk.UpdateAll(op.Filter, op.Update)
+			case op.Type == BulkUpdate:
+				bulk.Update(op.Filter, op.Update)
+			case op.Type == BulkRemove:
+				bulk.RemoveAll(op.Filter)
+			}
+		}
+
+		info, runErr := bulk.Run()
+		failed := make(map[int]error)
+		executed := end
+		if bulkErr, ok := runErr.(*mgo.BulkError); ok {
+			for _, errCase := range bulkErr.Cases() {
+				failed[start+errCase.Index] = mgoError(Bulk, errCase.Err)
+				if param.Ordered && start+errCase.Index+1 < executed {
+					executed = start + errCase.Index + 1
+				}
+			}
+		} else if runErr != nil {
+			log.Errorf("mgo bulk error with: %s", runErr)
+			for index := start; index < end; index++ {
+				failed[index] = mgoError(Bulk, runErr)
+			}
+		}
+		for index := start; index < executed; index++ {
+			result.add(param.Ops[index], index, nil, failed[index])
+		}
+		if info != nil {
+			result.Matched += int64(info.Matched)
+			result.Updated += int64(info.Modified)
+		}
+		if len(failed) > 0 && param.Ordered {
+			break
+		}
+	}
+	return result, result.Err()
+}
+
+// collection returns collection of storage namespace on given session
+func (m *MgoStorage) collection(session *mgo.Session) *mgo.Collection {
+	return session.DB(Database).C(m.namespace)
+}
+
+// copySession copies storage session with consistency profile of request
+// applied, caller must close session
+func (m *MgoStorage) copySession(ctx context.Context, op string, name string) (session *mgo.Session, err error) {
+	profile := m.profile
+	if name = profileName(ctx, name); name != "" && name != profile.Name {
+		if profile, err = m.config.NamedProfile(name); err != nil {
+			return nil, &Error{Op: op, Kind: ErrInvalid, Err: err}
+		}
+	}
+	session = m.session.Copy()
+	if profile != m.profile {
+		applyProfile(session, profile)
+	}
+	return
+}
+
+// applyProfile sets session mode, prefetch, batch size and write safety
+// based on consistency profile, read concern is not supported by mgo
+func applyProfile(session *mgo.Session, profile *cfg.Profile) {
+	session.SetMode(profile.ReadPref, true)
+	session.SetPrefetch(profile.Prefetch)
+	session.SetBatch(int(profile.BatchSize))
+	session.SetSafe(&mgo.Safe{
+		W:        int(profile.WriteOptions.Writeconcern),
+		WMode:    profile.WriteOptions.Writemode,
+		WTimeout: int(profile.WriteOptions.Writetimeout),
+		J:        profile.WriteOptions.J,
+	})
+}
+
+// query creates mgo query on a copied session, caller must close session
+func (m *MgoStorage) query(ctx context.Context, op string, query *QueryParam) (session *mgo.Session, q *mgo.Query, err error) {
+	if session, err = m.copySession(ctx, op, query.Profile); err != nil {
+		return
+	}
+	q = m.collection(session).Find(query.Filter)
+	if query.Fields != nil {
+		q = q.Select(query.Fields)
+	}
+	if len(query.Sort) > 0 {
+		q = q.Sort(query.Sort...)
+	}
+	if query.Skip > 0 {
+		q = q.Skip(int(query.Skip))
+	}
+	if query.Limit > 0 {
+		q = q.Limit(int(query.Limit))
+	}
+	if len(query.UsingIndex) > 0 {
+		q = q.Hint(query.UsingIndex...)
+	}
+	return
+}
+
+// mgoError wraps error returned by mgo driver into Error
+func mgoError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	e := &Error{Op: op, Err: err}
+	if err == mgo.ErrNotFound {
+		e.Kind = ErrNotFound
+	} else if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+		e.Kind = ErrTimeout
+	} else if err == io.EOF {
+		e.Kind = ErrUnhealthy
+	}
+	return e
+}
+
+// toChangeInfo converts mgo.ChangeInfo into mprpc.ChangeInfo
+func toChangeInfo(info *mgo.ChangeInfo) *mprpc.ChangeInfo {
+	if info == nil {
+		return &mprpc.ChangeInfo{}
+	}
+	return &mprpc.ChangeInfo{
+		Updated: int64(info.Updated),
+		Removed: int64(info.Removed),
+		Matched: int64(info.Matched),
+	}
+}
@@ -0,0 +1,235 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/xidongc/mongo_ebenchmark/mprpc"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"github.com/xidongc/mongo_ebenchmark/pkg/metrics"
+	"github.com/xidongc/mongo_ebenchmark/pkg/report"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"os"
+	"strconv"
+	"sync/atomic"
+)
+
+// Load balancing of rpcs over pooled connections
+const (
+	RoundRobin  = "round-robin"
+	LeastLoaded = "least-loaded"
+)
+
+// poolConn is a connection of Pool, inFlight counts rpcs and streams
+// running on it
+type poolConn struct {
+	*grpc.ClientConn
+	id       string
+	inFlight int64
+}
+
+// acquire marks a rpc started on conn, release must be called once
+func (c *poolConn) acquire() (release func()) {
+	atomic.AddInt64(&c.inFlight, 1)
+	done := metrics.PoolCall(c.id)
+	return func() {
+		atomic.AddInt64(&c.inFlight, -1)
+		done()
+	}
+}
+
+// Pool owns grpc connections to proxy shared by Clients of all
+// namespaces, rpcs are spread over connections by ProxyBalance, amp
+// jobs of all namespaces are queued to one amplifier
+//
+// Pool implements grpc.ClientConnInterface, so generated clients can
+// be created on it directly. Call Close once all Clients of pool are
+// closed
+type Pool struct {
+	config    *cfg.ProxyConfig
+	Host      string
+	ProtoFile string
+	conns     []*poolConn
+	next      uint64
+	pick      func() *poolConn
+	rpcClient mprpc.MongoProxyClient
+	amplifier *amplifier
+	reports   *report.Store
+}
+
+// NewPool dials ProxyPoolSize connections to proxy based on provided
+// cfg, and check for env: PROTOSET_FILE, see NewClient
+func NewPool(config *cfg.ProxyConfig) (pool *Pool, err error) {
+	if config == nil {
+		config = cfg.DefaultConfig()
+	}
+	if config.ProxyPort < 0 || config.ProxyPort == 0 {
+		log.Warning("port not defined properly, set back to default")
+		config.ProxyPort = 50051
+	}
+	size := config.ProxyPoolSize
+	if size <= 0 {
+		size = 1
+	}
+
+	pool = &Pool{
+		config:  config,
+		Host:    fmt.Sprintf("%s:%d", config.ProxyAddr, config.ProxyPort),
+		reports: report.NewStore(config.ReportDir, config.ReportFormat),
+	}
+	switch config.ProxyBalance {
+	case RoundRobin, "":
+		pool.pick = pool.roundRobin
+	case LeastLoaded:
+		pool.pick = pool.leastLoaded
+	default:
+		return nil, &Error{Op: "dial", Kind: ErrInvalid, Err: fmt.Errorf("unknown proxy balance %s", config.ProxyBalance)}
+	}
+
+	val, ok := os.LookupEnv("PROTOSET_FILE")
+	if !ok {
+		log.Warning("not found env PROTOSET_FILE, amplifier falls back to server reflection")
+	}
+	pool.ProtoFile = val
+
+	tlsConfig, err := config.ProxyTLS()
+	if err != nil {
+		return nil, &Error{Op: "dial", Kind: ErrInvalid, Err: err}
+	}
+	dialOption := grpc.WithInsecure()
+	if tlsConfig != nil {
+		dialOption = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+	}
+	for i := 0; i < size; i++ {
+		conn, err := grpc.Dial(pool.Host, dialOption)
+		if err != nil {
+			log.Errorf("connect to rpc cfg error: %s", err)
+			pool.closeConns()
+			return nil, &Error{Op: "dial", Kind: ErrUnhealthy, Err: err}
+		}
+		pool.conns = append(pool.conns, &poolConn{ClientConn: conn, id: strconv.Itoa(i)})
+	}
+	pool.rpcClient = mprpc.NewMongoProxyClient(pool)
+	pool.amplifier = newAmplifier(config.AmpQueueSize, config.AmpWorkers)
+	return
+}
+
+// Client returns a lightweight Client bound to collection namespace,
+// it shares connections and amplifier of pool
+//
+// Close of Client drains its amp jobs but keeps pool open
+func (pool *Pool) Client(namespace string, cancel context.CancelFunc) (client *Client, err error) {
+	if namespace == "" {
+		namespace = Collection
+	}
+	profile, err := pool.config.Profile(namespace)
+	if err != nil {
+		return nil, &Error{Op: "dial", Kind: ErrInvalid, Err: err}
+	}
+	client = &Client{
+		config: pool.config,
+		Host:   pool.Host,
+		Collection: &mprpc.Collection{
+			Database:   Database,
+			Collection: namespace,
+		},
+		profile:    profile,
+		pool:       pool,
+		rpcClient:  pool.rpcClient,
+		cancelFunc: cancel,
+		ProtoFile:  pool.ProtoFile,
+		reports:    pool.reports,
+	}
+	client.ampCtx, client.ampCancel = context.WithCancel(pool.amplifier.ctx)
+	return
+}
+
+// Size returns number of connections in pool
+func (pool *Pool) Size() int {
+	return len(pool.conns)
+}
+
+// Invoke performs a unary rpc on a connection picked by balance
+func (pool *Pool) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	conn := pool.pick()
+	release := conn.acquire()
+	defer release()
+	return conn.Invoke(ctx, method, args, reply, opts...)
+}
+
+// NewStream begins a streaming rpc on a connection picked by balance,
+// connection is counted as loaded until stream finished
+func (pool *Pool) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	conn := pool.pick()
+	release := conn.acquire()
+	stream, err := conn.NewStream(ctx, desc, method, opts...)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	// stream ctx is done once stream finished, whichever side ends it
+	go func() {
+		<-stream.Context().Done()
+		release()
+	}()
+	return stream, nil
+}
+
+// roundRobin picks connections in turn
+func (pool *Pool) roundRobin() *poolConn {
+	n := atomic.AddUint64(&pool.next, 1)
+	return pool.conns[n%uint64(len(pool.conns))]
+}
+
+// leastLoaded picks connection with fewest rpcs in flight, ties are
+// broken in turn so idle connections are still used evenly
+func (pool *Pool) leastLoaded() *poolConn {
+	start := atomic.AddUint64(&pool.next, 1)
+	size := uint64(len(pool.conns))
+	best := pool.conns[start%size]
+	for i := uint64(1); i < size; i++ {
+		conn := pool.conns[(start+i)%size]
+		if atomic.LoadInt64(&conn.inFlight) < atomic.LoadInt64(&best.inFlight) {
+			best = conn
+		}
+	}
+	return best
+}
+
+// Close cancels and drains amp jobs of all Clients, then closes
+// connections of pool
+func (pool *Pool) Close() (err error) {
+	pool.amplifier.close()
+	pool.amplifier.wg.Wait()
+	return pool.closeConns()
+}
+
+// closeConns closes all dialed connections, first error is returned
+func (pool *Pool) closeConns() (err error) {
+	for _, conn := range pool.conns {
+		if closeErr := conn.Close(); closeErr != nil {
+			log.Errorf("clean up failed: %s", closeErr)
+			if err == nil {
+				err = closeErr
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,154 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package proxy
+
+import (
+	"context"
+	"github.com/xidongc-wish/mgo/bson"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"github.com/xidongc/mongo_ebenchmark/pkg/proxy/proxytest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Test clients of a pool share connections but keep own collections
+func TestPool(t *testing.T) {
+	server, _, _ := proxytest.Start(t)
+
+	config := server.Config()
+	config.ProxyPoolSize = 3
+	config.ProxyBalance = LeastLoaded
+	pool, err := NewPool(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := pool.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if pool.Size() != 3 {
+		t.Errorf("expect 3 connections, got %d", pool.Size())
+	}
+
+	ctx := context.Background()
+	first, err := pool.Client("pool_first", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := pool.Client("pool_second", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := first.Insert(ctx, &InsertParam{Docs: []interface{}{bson.M{"name": "mavic"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.Insert(ctx, &InsertParam{Docs: []interface{}{bson.M{"name": "osmo"}, bson.M{"name": "care"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if docs := server.Docs(Database, "pool_first"); len(docs) != 1 {
+		t.Errorf("expect 1 document in first namespace, got %d", len(docs))
+	}
+	if docs := server.Docs(Database, "pool_second"); len(docs) != 2 {
+		t.Errorf("expect 2 documents in second namespace, got %d", len(docs))
+	}
+
+	// closing a client leaves pool open for others
+	if err := first.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.HealthCheck(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	stream, err := second.FindIter(ctx, &QueryParam{Filter: bson.M{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		if _, err := stream.Recv(); err != nil {
+			break
+		}
+	}
+	deadline := time.Now().Add(time.Second)
+	for _, conn := range pool.conns {
+		for atomic.LoadInt64(&conn.inFlight) != 0 && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if n := atomic.LoadInt64(&conn.inFlight); n != 0 {
+			t.Errorf("expect connection %s released, %d in flight", conn.id, n)
+		}
+	}
+}
+
+// Test connections are picked by balance
+func TestPoolBalance(t *testing.T) {
+	pool := &Pool{conns: []*poolConn{{id: "0"}, {id: "1"}, {id: "2"}}}
+
+	picked := make(map[string]int)
+	for i := 0; i < 6; i++ {
+		picked[pool.roundRobin().id]++
+	}
+	for _, conn := range pool.conns {
+		if picked[conn.id] != 2 {
+			t.Errorf("expect connection %s picked twice, got %d", conn.id, picked[conn.id])
+		}
+	}
+
+	pool.conns[0].inFlight = 2
+	pool.conns[1].inFlight = 1
+	pool.conns[2].inFlight = 3
+	for i := 0; i < 3; i++ {
+		if conn := pool.leastLoaded(); conn.id != "1" {
+			t.Errorf("expect least loaded connection 1, got %s", conn.id)
+		}
+	}
+}
+
+// Test manager hands out storages of each namespace
+func TestManager(t *testing.T) {
+	config := cfg.DefaultConfig()
+	config.Backend = MemoryBackend
+	manager, err := NewManager(config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := manager.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	ctx := context.Background()
+	first, _ := manager.Storage("manager_first")
+	second, _ := manager.Storage("manager_second")
+	if err := first.Insert(ctx, &InsertParam{Docs: []interface{}{bson.M{"name": "mavic"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if count, err := second.Count(ctx, &QueryParam{Filter: bson.M{}}); err != nil || count != 0 {
+		t.Errorf("expect second namespace empty, got %d, %v", count, err)
+	}
+	if count, err := first.Count(ctx, &QueryParam{Filter: bson.M{}}); err != nil || count != 1 {
+		t.Errorf("expect 1 document in first namespace, got %d, %v", count, err)
+	}
+
+	config.Backend = "unknown"
+	if _, err := NewManager(config, nil); err == nil {
+		t.Error("expect unknown backend rejected")
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package proxy
+
+import (
+	"context"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+// ProfileMetadataKey is grpc metadata key api callers set to select
+// consistency profile of a single request, eg: x-consistency-profile: causal
+const ProfileMetadataKey = "x-consistency-profile"
+
+type profileKey struct{}
+
+// WithProfile returns a copy of ctx selecting consistency profile of
+// given name, storage requests issued with ctx use it unless param
+// specifies a Profile
+func WithProfile(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, profileKey{}, name)
+}
+
+// profileName returns consistency profile selected for a request, in
+// order of param, WithProfile and incoming grpc metadata, empty means
+// storage default
+func profileName(ctx context.Context, name string) string {
+	if name != "" {
+		return name
+	}
+	if name, ok := ctx.Value(profileKey{}).(string); ok && name != "" {
+		return name
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(ProfileMetadataKey); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// consistency returns consistency profile of a request, client profile
+// is used if request does not select one
+func (client *Client) consistency(ctx context.Context, op string, name string) (*cfg.Profile, error) {
+	name = profileName(ctx, name)
+	if name == "" || name == client.profile.Name {
+		trace.SpanFromContext(ctx).SetAttributes(attrBatchSize.Int64(client.profile.BatchSize))
+		return client.profile, nil
+	}
+	profile, err := client.config.NamedProfile(name)
+	if err != nil {
+		return nil, &Error{Op: op, Kind: ErrInvalid, Err: err}
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attrBatchSize.Int64(profile.BatchSize))
+	return profile, nil
+}
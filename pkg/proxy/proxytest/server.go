@@ -0,0 +1,368 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+// Package proxytest provides an in-memory mprpc.MongoProxy server
+// for hermetic tests, it listens on loopback so both proxy.NewClient
+// and ghz amplifier can connect to it unchanged
+package proxytest
+
+import (
+	"context"
+	"fmt"
+	"github.com/xidongc-wish/mgo/bson"
+	"github.com/xidongc/mongo_ebenchmark/mprpc"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"github.com/xidongc/mongo_ebenchmark/pkg/proxy/memdb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Server is a fake mongo proxy backed by in-memory collections
+//
+// Server is safe for concurrent use, documents are stored in memdb
+// per database.collection namespace
+type Server struct {
+	mprpc.UnimplementedMongoProxyServer
+
+	Addr     string
+	Port     int
+	listener net.Listener
+	svr      *grpc.Server
+
+	db *memdb.DB
+}
+
+// NewServer starts a fake proxy on a random loopback port, server
+// reflection is registered so ghz can be used without protoset
+//
+// Call Close to stop the server once test finished
+func NewServer() (server *Server, err error) {
+	lis, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		return
+	}
+	server = &Server{
+		Addr:     "127.0.0.1",
+		Port:     lis.Addr().(*net.TCPAddr).Port,
+		listener: lis,
+		svr:      grpc.NewServer(),
+		db:       memdb.New(),
+	}
+	mprpc.RegisterMongoProxyServer(server.svr, server)
+	reflection.Register(server.svr)
+
+	go func() {
+		_ = server.svr.Serve(lis)
+	}()
+	return
+}
+
+// Start starts a fake proxy for test tb, server is closed and returned
+// context is cancelled on test cleanup
+func Start(tb testing.TB) (*Server, context.Context, context.CancelFunc) {
+	tb.Helper()
+	server, err := NewServer()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	tb.Cleanup(func() {
+		cancel()
+		server.Close()
+	})
+	return server, ctx, cancel
+}
+
+// Config returns proxy config pointing at fake server, PROTOSET_FILE
+// is set to empty if absent, ghz then falls back to server reflection,
+// amp reports are stored in temp dir
+func (s *Server) Config() (config *cfg.ProxyConfig) {
+	if _, ok := os.LookupEnv("PROTOSET_FILE"); !ok {
+		_ = os.Setenv("PROTOSET_FILE", "")
+	}
+	config = cfg.DefaultConfig()
+	config.ProxyAddr = s.Addr
+	config.ProxyPort = s.Port
+	config.ReportDir = filepath.Join(os.TempDir(), "ebenchmark-results")
+	return
+}
+
+// Host returns address of fake server in host:port format
+func (s *Server) Host() string {
+	return fmt.Sprintf("%s:%d", s.Addr, s.Port)
+}
+
+// Close stops the server and drops all stored documents
+func (s *Server) Close() {
+	s.svr.Stop()
+	s.db.Drop()
+}
+
+// Docs returns a copy of documents stored in given namespace
+func (s *Server) Docs(database string, collection string) []bson.M {
+	return s.db.Find(namespace(&mprpc.Collection{Database: database, Collection: collection}), &memdb.Query{})
+}
+
+// Find returns documents matching filter, honour skip, limit, sort
+// and fields projection
+func (s *Server) Find(ctx context.Context, query *mprpc.FindQuery) (*mprpc.QueryResult, error) {
+	docs, err := s.find(query)
+	if err != nil {
+		return nil, err
+	}
+	return toQueryResult(docs)
+}
+
+// FindIter works like Find, but sends results in batches of Batchsize
+func (s *Server) FindIter(query *mprpc.FindQuery, stream mprpc.MongoProxy_FindIterServer) error {
+	docs, err := s.find(query)
+	if err != nil {
+		return err
+	}
+	batchSize := int(query.GetBatchsize())
+	if batchSize <= 0 {
+		batchSize = len(docs)
+	}
+	for start := 0; start < len(docs); start += batchSize {
+		end := start + batchSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+		result, err := toQueryResult(docs[start:end])
+		if err != nil {
+			return err
+		}
+		if err = stream.Send(result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Count returns number of documents matching filter
+func (s *Server) Count(ctx context.Context, query *mprpc.FindQuery) (*mprpc.CountResult, error) {
+	docs, err := s.find(query)
+	if err != nil {
+		return nil, err
+	}
+	return &mprpc.CountResult{Count: int64(len(docs))}, nil
+}
+
+// Distinct returns distinct values of Distinctkey wrapped as {values: [...]}
+func (s *Server) Distinct(ctx context.Context, query *mprpc.FindQuery) (*mprpc.Document, error) {
+	filter, err := unmarshal(query.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	values := s.db.Distinct(namespace(query.GetCollection()), query.GetDistinctkey(), filter)
+	return toDocument(bson.M{"values": values})
+}
+
+// Insert stores documents, _id is generated if not specified
+func (s *Server) Insert(ctx context.Context, op *mprpc.InsertOperation) (*mprpc.ChangeInfo, error) {
+	var docs []bson.M
+	for _, document := range op.GetDocuments() {
+		doc, err := unmarshal(document.GetVal())
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	inserted := s.db.Insert(namespace(op.GetCollection()), docs)
+	return &mprpc.ChangeInfo{Updated: int64(inserted)}, nil
+}
+
+// Update modifies first or all matched documents, inserts if upsert
+// and nothing matched
+func (s *Server) Update(ctx context.Context, op *mprpc.UpdateOperation) (*mprpc.ChangeInfo, error) {
+	filter, err := unmarshal(op.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	update, err := unmarshal(op.GetUpdate())
+	if err != nil {
+		return nil, err
+	}
+	matched, updated, err := s.db.Update(namespace(op.GetCollection()), filter, update, op.GetUpsert(), op.GetMulti())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &mprpc.ChangeInfo{Matched: int64(matched), Updated: int64(updated)}, nil
+}
+
+// Remove deletes all documents matching filter
+func (s *Server) Remove(ctx context.Context, op *mprpc.RemoveOperation) (*mprpc.ChangeInfo, error) {
+	filter, err := unmarshal(op.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	removed := s.db.Remove(namespace(op.GetCollection()), filter)
+	return &mprpc.ChangeInfo{Removed: int64(removed)}, nil
+}
+
+// FindAndModify atomically updates, upserts or removes the first matched
+// document, returns the old document unless New is specified
+func (s *Server) FindAndModify(ctx context.Context, op *mprpc.FindAndModifyOperation) (*mprpc.Document, error) {
+	filter, err := unmarshal(op.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	update, err := unmarshal(op.GetUpdate())
+	if err != nil {
+		return nil, err
+	}
+	fields, err := unmarshal(op.GetFields())
+	if err != nil {
+		return nil, err
+	}
+	change := &memdb.Change{
+		Update:    update,
+		Upsert:    op.GetUpsert(),
+		Remove:    op.GetRemove(),
+		ReturnNew: op.GetNew(),
+	}
+	result, err := s.db.FindAndModify(namespace(op.GetCollection()), filter, nil, change, fields)
+	if err == memdb.ErrNotFound {
+		return nil, status.Error(codes.NotFound, err.Error())
+	} else if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if result == nil {
+		return &mprpc.Document{}, nil
+	}
+	return toDocument(result)
+}
+
+// Bulk applies operations in order, failed operation is reported in
+// its result, ordered bulk stops at the first failure
+func (s *Server) Bulk(ctx context.Context, op *mprpc.BulkOperation) (*mprpc.BulkResult, error) {
+	ns := namespace(op.GetCollection())
+	result := &mprpc.BulkResult{}
+	for i, bulkOp := range op.GetOps() {
+		changeInfo, err := s.bulkOp(ns, bulkOp)
+		opResult := &mprpc.BulkOpResult{Index: int32(i), Changeinfo: changeInfo}
+		if err != nil {
+			opResult.Error = err.Error()
+		}
+		result.Results = append(result.Results, opResult)
+		if err != nil && op.GetOrdered() {
+			break
+		}
+	}
+	return result, nil
+}
+
+// Healthcheck always succeed while server is serving
+func (s *Server) Healthcheck(ctx context.Context, empty *mprpc.Empty) (*mprpc.Empty, error) {
+	return &mprpc.Empty{}, nil
+}
+
+// bulkOp applies a single bulk operation on namespace
+func (s *Server) bulkOp(ns string, op *mprpc.BulkOp) (*mprpc.ChangeInfo, error) {
+	filter, err := unmarshal(op.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	switch op.GetType() {
+	case mprpc.BulkOp_INSERT:
+		doc, err := unmarshal(op.GetDocument())
+		if err != nil {
+			return nil, err
+		}
+		return &mprpc.ChangeInfo{Updated: int64(s.db.Insert(ns, []bson.M{doc}))}, nil
+	case mprpc.BulkOp_UPDATE:
+		update, err := unmarshal(op.GetUpdate())
+		if err != nil {
+			return nil, err
+		}
+		matched, updated, err := s.db.Update(ns, filter, update, op.GetUpsert(), op.GetMulti())
+		if err != nil {
+			return nil, err
+		}
+		return &mprpc.ChangeInfo{Matched: int64(matched), Updated: int64(updated)}, nil
+	case mprpc.BulkOp_REMOVE:
+		return &mprpc.ChangeInfo{Removed: int64(s.db.Remove(ns, filter))}, nil
+	}
+	return nil, fmt.Errorf("unknown bulk op type %s", op.GetType())
+}
+
+// find decodes query and returns matched documents
+func (s *Server) find(query *mprpc.FindQuery) (docs []bson.M, err error) {
+	filter, err := unmarshal(query.GetFilter())
+	if err != nil {
+		return
+	}
+	fields, err := unmarshal(query.GetFields())
+	if err != nil {
+		return
+	}
+	limit := int(query.GetLimit())
+	if query.GetFindone() {
+		limit = 1
+	}
+	docs = s.db.Find(namespace(query.GetCollection()), &memdb.Query{
+		Filter: filter,
+		Fields: fields,
+		Sort:   query.GetSort(),
+		Skip:   int(query.GetSkip()),
+		Limit:  limit,
+	})
+	return
+}
+
+// namespace returns database.collection of given collection
+func namespace(collection *mprpc.Collection) string {
+	return fmt.Sprintf("%s.%s", collection.GetDatabase(), collection.GetCollection())
+}
+
+// unmarshal decodes bson bytes, empty bytes are decoded as nil document
+func unmarshal(b []byte) (doc bson.M, err error) {
+	if len(b) == 0 {
+		return
+	}
+	if err = bson.Unmarshal(b, &doc); err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+	}
+	return
+}
+
+// toDocument marshals a single document into mprpc.Document
+func toDocument(doc bson.M) (*mprpc.Document, error) {
+	b, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &mprpc.Document{Val: b}, nil
+}
+
+// toQueryResult marshals documents into mprpc.QueryResult
+func toQueryResult(docs []bson.M) (*mprpc.QueryResult, error) {
+	result := &mprpc.QueryResult{}
+	for _, doc := range docs {
+		document, err := toDocument(doc)
+		if err != nil {
+			return nil, err
+		}
+		result.Results = append(result.Results, document)
+	}
+	return result, nil
+}
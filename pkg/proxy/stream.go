@@ -0,0 +1,78 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package proxy
+
+import (
+	"github.com/xidongc-wish/mgo/bson"
+	"github.com/xidongc/mongo_ebenchmark/mprpc"
+	"google.golang.org/grpc"
+	"io"
+)
+
+// docStream adapts documents fetched by non grpc backends to
+// mprpc.MongoProxy_FindIterClient, so FindIter keeps the same
+// interface across storage backends
+//
+// only Recv is supported, grpc.ClientStream methods are not
+// available since there is no underlying grpc stream
+type docStream struct {
+	grpc.ClientStream
+	next      func() (doc bson.M, ok bool, err error)
+	batchSize int
+}
+
+// Recv returns next batch of documents, io.EOF once exhausted
+func (s *docStream) Recv() (result *mprpc.QueryResult, err error) {
+	result = &mprpc.QueryResult{}
+	for len(result.Results) < s.batchSize {
+		doc, ok, err := s.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		val, err := bson.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		result.Results = append(result.Results, &mprpc.Document{Val: val})
+	}
+	if len(result.Results) == 0 {
+		return nil, io.EOF
+	}
+	return
+}
+
+// sliceStream creates a docStream iterating over given documents
+func sliceStream(docs []bson.M, batchSize int64) *docStream {
+	if batchSize <= 0 {
+		batchSize = int64(len(docs)) + 1
+	}
+	index := 0
+	return &docStream{
+		next: func() (doc bson.M, ok bool, err error) {
+			if index >= len(docs) {
+				return nil, false, nil
+			}
+			doc = docs[index]
+			index++
+			return doc, true, nil
+		},
+		batchSize: int(batchSize),
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package proxy
+
+import (
+	"context"
+	"github.com/xidongc-wish/mgo/bson"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"sort"
+	"strings"
+)
+
+// Attributes of proxy client spans
+const (
+	attrNamespace = attribute.Key("db.namespace")
+	attrFilter    = attribute.Key("db.filter")
+	attrBatchSize = attribute.Key("db.batch_size")
+	attrDocuments = attribute.Key("db.documents")
+)
+
+// traceDocuments records number of documents returned or written by a call in
+// its span
+func traceDocuments(ctx context.Context, n int64) {
+	trace.SpanFromContext(ctx).SetAttributes(attrDocuments.Int64(n))
+}
+
+// filterShape returns filter with values replaced by ?, so spans of
+// queries differing only in values look the same, eg:
+//
+//     {"name":?,"price":{"$gte":?}}
+//
+func filterShape(filter interface{}) string {
+	var b strings.Builder
+	writeShape(&b, filter)
+	return b.String()
+}
+
+func writeShape(b *strings.Builder, value interface{}) {
+	switch value := value.(type) {
+	case bson.M:
+		writeShapeDoc(b, value)
+	case map[string]interface{}:
+		writeShapeDoc(b, value)
+	case bson.D:
+		doc := make(bson.M, len(value))
+		for _, elem := range value {
+			doc[elem.Name] = elem.Value
+		}
+		writeShapeDoc(b, doc)
+	case []bson.M:
+		b.WriteByte('[')
+		for i, elem := range value {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeShapeDoc(b, elem)
+		}
+		b.WriteByte(']')
+	case []interface{}:
+		// array of values, eg: $in, is a single ?
+		b.WriteByte('[')
+		for i, elem := range value {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if !isDoc(elem) {
+				b.WriteByte('?')
+				break
+			}
+			writeShape(b, elem)
+		}
+		b.WriteByte(']')
+	default:
+		b.WriteByte('?')
+	}
+}
+
+// writeShapeDoc writes doc with sorted keys
+func writeShapeDoc(b *strings.Builder, doc map[string]interface{}) {
+	keys := make([]string, 0, len(doc))
+	for key := range doc {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	b.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(`"` + key + `":`)
+		writeShape(b, doc[key])
+	}
+	b.WriteByte('}')
+}
+
+func isDoc(value interface{}) bool {
+	switch value.(type) {
+	case bson.M, map[string]interface{}, bson.D:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package proxy
+
+import (
+	"github.com/xidongc-wish/mgo/bson"
+	"testing"
+)
+
+// Test on filter shape recorded in spans
+func TestFilterShape(t *testing.T) {
+	for _, c := range []struct {
+		filter   interface{}
+		expected string
+	}{
+		{bson.M{}, `{}`},
+		{bson.M{"name": "mavic", "price": bson.M{"$gte": 100}}, `{"name":?,"price":{"$gte":?}}`},
+		{bson.M{"id": bson.M{"$in": []interface{}{"a", "b", "c"}}}, `{"id":{"$in":[?]}}`},
+		{bson.M{"$or": []interface{}{bson.M{"a": 1}, bson.M{"b": bson.D{{Name: "$exists", Value: true}}}}}, `{"$or":[{"a":?},{"b":{"$exists":?}}]}`},
+		{[]bson.M{{"$match": bson.M{"productid": "p"}}, {"$limit": 10}}, `[{"$match":{"productid":?}},{"$limit":?}]`},
+		{bson.M{"$in": []interface{}{bson.M{"a": 1}, 2, 3}}, `{"$in":[{"a":?},?]}`},
+	} {
+		if shape := filterShape(c.filter); shape != c.expected {
+			t.Errorf("expect %s, got %s", c.expected, shape)
+		}
+	}
+}
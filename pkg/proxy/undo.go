@@ -23,8 +23,8 @@ import (
 	"strings"
 )
 
-// UndoInsert generate removeParam based on given insert param
-// Currently nested struct is not supported
+// UndoInsert generate removeParam based on given insert param, docs are
+// structs or pointers to struct. Currently nested struct is not supported
 //
 // Please refer proxy.InsertParam, proxy.RemoveParam for more details
 func UndoInsert(param *InsertParam) (params []*RemoveParam) {
@@ -34,8 +34,8 @@ func UndoInsert(param *InsertParam) (params []*RemoveParam) {
 	}
 	for _, doc := range param.Docs {
 		var removeFilter = bson.M{}
-		objType := reflect.TypeOf(doc)
-		objVal := reflect.ValueOf(doc)
+		objVal := reflect.Indirect(reflect.ValueOf(doc))
+		objType := objVal.Type()
 		for i := 0; i < objVal.NumField(); i++ {
 			if objVal.Field(i).CanInterface() {
 				if objVal.Field(i).Kind() == reflect.Struct {
@@ -31,7 +31,7 @@ func TestUndoInsert(t *testing.T) {
 		Amount:      5,
 		Description: "test description",
 	}
-	item2 := orderpb.Item{
+	item2 := &orderpb.Item{
 		ProductId:   "pid12345",
 		Name:        "TestItem",
 		Quantity:    4,
@@ -49,6 +49,9 @@ func TestUndoInsert(t *testing.T) {
 	if len(removeParam) != 2 {
 		t.Error("error")
 	}
+	if removeParam[1].Filter["productid"] != "pid12345" {
+		t.Errorf("expect filter of pointer doc, got %v", removeParam[1].Filter)
+	}
 	t.Log(*removeParam[0])
 	t.Log(*removeParam[1])
 }
@@ -0,0 +1,258 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package report
+
+import (
+	"fmt"
+	"github.com/bojand/ghz/runner"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Key aligns records of different runs
+type Key struct {
+	Method    string
+	Namespace string
+}
+
+func (k Key) String() string {
+	return fmt.Sprintf("%s@%s", k.Method, k.Namespace)
+}
+
+// Summary is the part of ghz report runs are compared on
+type Summary struct {
+	P50       time.Duration
+	P90       time.Duration
+	P99       time.Duration
+	Rps       float64
+	ErrorRate float64
+}
+
+// Summarize extracts Summary from ghz report
+func Summarize(r *runner.Report) (summary Summary) {
+	if r == nil {
+		return
+	}
+	for _, l := range r.LatencyDistribution {
+		switch l.Percentage {
+		case 50:
+			summary.P50 = l.Latency
+		case 90:
+			summary.P90 = l.Latency
+		case 99:
+			summary.P99 = l.Latency
+		}
+	}
+	summary.Rps = r.Rps
+	if r.Count > 0 {
+		failed := 0
+		for _, n := range r.ErrorDist {
+			failed += n
+		}
+		summary.ErrorRate = float64(failed) / float64(r.Count)
+	}
+	return
+}
+
+// Delta is the change of a candidate run against baseline run
+type Delta struct {
+	Key
+	Base      Summary
+	Candidate Summary
+}
+
+// P50 returns p50 latency change in percent, positive is slower
+func (d Delta) P50() float64 {
+	return change(float64(d.Base.P50), float64(d.Candidate.P50))
+}
+
+// P90 returns p90 latency change in percent, positive is slower
+func (d Delta) P90() float64 {
+	return change(float64(d.Base.P90), float64(d.Candidate.P90))
+}
+
+// P99 returns p99 latency change in percent, positive is slower
+func (d Delta) P99() float64 {
+	return change(float64(d.Base.P99), float64(d.Candidate.P99))
+}
+
+// Rps returns rps change in percent, negative is slower
+func (d Delta) Rps() float64 {
+	return change(d.Base.Rps, d.Candidate.Rps)
+}
+
+// ErrorRate returns error rate change in percentage points
+func (d Delta) ErrorRate() float64 {
+	return (d.Candidate.ErrorRate - d.Base.ErrorRate) * 100
+}
+
+// Thresholds are max regression allowed in percent, 0 disables check
+//
+// latency thresholds bound increase of latency, Rps bounds decrease
+// of rps, ErrorRate bounds increase of error rate in percentage points
+type Thresholds struct {
+	P50       float64
+	P90       float64
+	P99       float64
+	Rps       float64
+	ErrorRate float64
+}
+
+// Violations returns a description of each threshold exceeded by delta
+func (t Thresholds) Violations(d Delta) (violations []string) {
+	check := func(name string, limit float64, value float64) {
+		if limit > 0 && value > limit {
+			violations = append(violations, fmt.Sprintf("%s: %s regressed %.2f%% (max %.2f%%)", d.Key, name, value, limit))
+		}
+	}
+	check("p50", t.P50, d.P50())
+	check("p90", t.P90, d.P90())
+	check("p99", t.P99, d.P99())
+	check("rps", t.Rps, -d.Rps())
+	check("error rate", t.ErrorRate, d.ErrorRate())
+	return
+}
+
+// LoadRecords reads json records from path, path is either a record
+// file or a directory walked recursively, latest record is kept when
+// several records share the same Key
+func LoadRecords(path string) (records map[Key]*Record, err error) {
+	records = make(map[Key]*Record)
+	err = filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(file, ".json") {
+			return nil
+		}
+		record, err := Load(file)
+		if err != nil {
+			return err
+		}
+		key := Key{Method: record.Method, Namespace: record.Namespace}
+		if prev, ok := records[key]; !ok || record.Timestamp.After(prev.Timestamp) {
+			records[key] = record
+		}
+		return nil
+	})
+	return
+}
+
+// Comparison is the result of aligning candidate records with baseline
+type Comparison struct {
+	Deltas []Delta
+	// keys only found in baseline, eg: method dropped by candidate run
+	BaseOnly []Key
+	// keys only found in candidate, eg: method added by candidate run
+	CandidateOnly []Key
+}
+
+// Compare aligns candidate records with baseline by Key, keys missing
+// on either side are reported separately, all results sorted by Key
+func Compare(base map[Key]*Record, candidate map[Key]*Record) (comparison Comparison) {
+	for key, c := range candidate {
+		b, ok := base[key]
+		if !ok {
+			comparison.CandidateOnly = append(comparison.CandidateOnly, key)
+			continue
+		}
+		comparison.Deltas = append(comparison.Deltas, Delta{
+			Key:       key,
+			Base:      Summarize(b.Report),
+			Candidate: Summarize(c.Report),
+		})
+	}
+	for key := range base {
+		if _, ok := candidate[key]; !ok {
+			comparison.BaseOnly = append(comparison.BaseOnly, key)
+		}
+	}
+	sort.Slice(comparison.Deltas, func(i, j int) bool {
+		return comparison.Deltas[i].Key.String() < comparison.Deltas[j].Key.String()
+	})
+	sortKeys(comparison.BaseOnly)
+	sortKeys(comparison.CandidateOnly)
+	return
+}
+
+// Gate selects keys thresholds are enforced on, empty field matches all
+type Gate struct {
+	Method    string
+	Namespace string
+}
+
+// Matches reports whether key is gated
+func (g Gate) Matches(key Key) bool {
+	return (g.Method == "" || g.Method == key.Method) && (g.Namespace == "" || g.Namespace == key.Namespace)
+}
+
+// Violations returns threshold violations of gated deltas, a gated key
+// missing on either side, or a gate without any delta is a violation
+// too since nothing was actually compared
+func (g Gate) Violations(comparison Comparison, thresholds Thresholds) (violations []string) {
+	compared := false
+	for _, d := range comparison.Deltas {
+		if !g.Matches(d.Key) {
+			continue
+		}
+		compared = true
+		violations = append(violations, thresholds.Violations(d)...)
+	}
+	for _, key := range comparison.BaseOnly {
+		if g.Matches(key) {
+			violations = append(violations, fmt.Sprintf("%s: missing in candidate", key))
+		}
+	}
+	for _, key := range comparison.CandidateOnly {
+		if g.Matches(key) {
+			violations = append(violations, fmt.Sprintf("%s: missing in baseline", key))
+		}
+	}
+	if !compared {
+		violations = append(violations, fmt.Sprintf("%s: no delta to compare", g))
+	}
+	return
+}
+
+func (g Gate) String() string {
+	method, namespace := g.Method, g.Namespace
+	if method == "" {
+		method = "*"
+	}
+	if namespace == "" {
+		namespace = "*"
+	}
+	return Key{Method: method, Namespace: namespace}.String()
+}
+
+// sortKeys sorts keys by their string form
+func sortKeys(keys []Key) {
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+}
+
+// change returns relative change from base to value in percent
+func change(base float64, value float64) float64 {
+	if base == 0 {
+		return 0
+	}
+	return (value - base) / base * 100
+}
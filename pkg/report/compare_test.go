@@ -0,0 +1,107 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package report
+
+import (
+	"github.com/bojand/ghz/runner"
+	"testing"
+	"time"
+)
+
+func record(method string, namespace string, p99 time.Duration, rps float64) *Record {
+	return &Record{
+		Method:    method,
+		Namespace: namespace,
+		Report: &runner.Report{
+			Count: 100,
+			Rps:   rps,
+			LatencyDistribution: []runner.LatencyDistribution{
+				{Percentage: 99, Latency: p99},
+			},
+		},
+	}
+}
+
+// Test on comparing runs against thresholds
+func TestCompare(t *testing.T) {
+	find := Key{Method: "mprpc.MongoProxy.Find", Namespace: "sku"}
+	insert := Key{Method: "mprpc.MongoProxy.Insert", Namespace: "sku"}
+	base := map[Key]*Record{
+		find:   record(find.Method, find.Namespace, 100*time.Millisecond, 1000),
+		insert: record(insert.Method, insert.Namespace, 100*time.Millisecond, 1000),
+	}
+	candidate := map[Key]*Record{
+		find:   record(find.Method, find.Namespace, 106*time.Millisecond, 1000),
+		insert: record(insert.Method, insert.Namespace, 104*time.Millisecond, 900),
+	}
+
+	deltas := Compare(base, candidate).Deltas
+	if len(deltas) != 2 || deltas[0].Key != find {
+		t.Fatalf("unexpected deltas %+v", deltas)
+	}
+
+	thresholds := Thresholds{P99: 5}
+	if v := thresholds.Violations(deltas[0]); len(v) != 1 {
+		t.Errorf("expect p99 regression on find, got %v", v)
+	}
+	if v := thresholds.Violations(deltas[1]); len(v) != 0 {
+		t.Errorf("expect no regression on insert, got %v", v)
+	}
+
+	thresholds = Thresholds{Rps: 5}
+	if v := thresholds.Violations(deltas[1]); len(v) != 1 {
+		t.Errorf("expect rps regression on insert, got %v", v)
+	}
+}
+
+// Test on keys found on one side only
+func TestCompareMissing(t *testing.T) {
+	find := Key{Method: "mprpc.MongoProxy.Find", Namespace: "sku"}
+	insert := Key{Method: "mprpc.MongoProxy.Insert", Namespace: "sku"}
+	remove := Key{Method: "mprpc.MongoProxy.Remove", Namespace: "order"}
+	base := map[Key]*Record{
+		find:   record(find.Method, find.Namespace, 100*time.Millisecond, 1000),
+		insert: record(insert.Method, insert.Namespace, 100*time.Millisecond, 1000),
+	}
+	candidate := map[Key]*Record{
+		find:   record(find.Method, find.Namespace, 100*time.Millisecond, 1000),
+		remove: record(remove.Method, remove.Namespace, 100*time.Millisecond, 1000),
+	}
+
+	comparison := Compare(base, candidate)
+	if len(comparison.Deltas) != 1 || len(comparison.BaseOnly) != 1 || comparison.BaseOnly[0] != insert ||
+		len(comparison.CandidateOnly) != 1 || comparison.CandidateOnly[0] != remove {
+		t.Fatalf("unexpected comparison %+v", comparison)
+	}
+
+	thresholds := Thresholds{P99: 5}
+	if v := (Gate{Method: find.Method, Namespace: find.Namespace}).Violations(comparison, thresholds); len(v) != 0 {
+		t.Errorf("expect no violation on find, got %v", v)
+	}
+	if v := (Gate{Method: insert.Method}).Violations(comparison, thresholds); len(v) != 2 {
+		t.Errorf("expect insert missing in candidate without delta, got %v", v)
+	}
+	if v := (Gate{Namespace: "order"}).Violations(comparison, thresholds); len(v) != 2 {
+		t.Errorf("expect order missing in baseline without delta, got %v", v)
+	}
+	if v := (Gate{}).Violations(comparison, thresholds); len(v) != 2 {
+		t.Errorf("expect both missing keys reported, got %v", v)
+	}
+	if v := (Gate{Namespace: "user"}).Violations(Compare(base, candidate), thresholds); len(v) != 1 {
+		t.Errorf("expect gate without delta rejected, got %v", v)
+	}
+}
@@ -0,0 +1,181 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+// Package report stores ghz reports of amplification runs, each run
+// is saved as json record together with config it was running with,
+// and optionally exported in other formats for dashboards
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/bojand/ghz/printer"
+	"github.com/bojand/ghz/runner"
+	"github.com/xidongc/mongo_ebenchmark/mprpc"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"github.com/xidongc/mongo_ebenchmark/pkg/metrics"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Supported output formats
+const (
+	JSON       = "json"
+	CSV        = "csv"
+	HTML       = "html"
+	InfluxLine = "influx-line"
+	Prometheus = "prometheus"
+)
+
+// timeLayout is used as filename prefix, sortable and path safe
+const timeLayout = "20060102T150405.000"
+
+// RunConfig is the config amplification run was running with
+type RunConfig struct {
+	Turbo        bool                `json:"turbo"`
+	Profile      string              `json:"profile"`
+	ReadConcern  string              `json:"readConcern"`
+	WriteOptions *mprpc.WriteOptions `json:"writeOptions"`
+	Amplify      cfg.AmplifyOptions  `json:"amplify"`
+}
+
+// Record is one amplification run of a rpc method on a namespace
+type Record struct {
+	Method    string         `json:"method"`
+	Namespace string         `json:"namespace"`
+	Timestamp time.Time      `json:"timestamp"`
+	Config    RunConfig      `json:"config"`
+	Report    *runner.Report `json:"report"`
+}
+
+// Store saves records under Dir/<namespace>/, file is named as
+// <timestamp>_<method>.<ext>
+type Store struct {
+	Dir    string
+	Format string
+}
+
+// NewStore creates report store, json is used if format is empty
+func NewStore(dir string, format string) *Store {
+	if dir == "" {
+		dir = "results"
+	}
+	if format == "" {
+		format = JSON
+	}
+	return &Store{
+		Dir:    dir,
+		Format: format,
+	}
+}
+
+// Save writes record as json, and in Store.Format as well if it is
+// not json, path of json record is returned
+func (s *Store) Save(record *Record) (path string, err error) {
+	if record == nil || record.Report == nil {
+		return "", fmt.Errorf("report: empty record")
+	}
+	if record.Timestamp.IsZero() {
+		record.Timestamp = time.Now()
+	}
+	dir := filepath.Join(s.Dir, record.Namespace)
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+	base := filepath.Join(dir, fmt.Sprintf("%s_%s", record.Timestamp.UTC().Format(timeLayout), record.Method))
+
+	path = base + ".json"
+	if err = writeFile(path, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(record)
+	}); err != nil {
+		return
+	}
+	if s.Format == JSON {
+		return
+	}
+	err = writeFile(base+extension(s.Format), func(w io.Writer) error {
+		return Export(w, record, s.Format)
+	})
+	return
+}
+
+// Load reads a json record saved by Store
+func Load(path string) (record *Record, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	record = &Record{}
+	if err = json.NewDecoder(file).Decode(record); err != nil {
+		return nil, fmt.Errorf("report: decode %s: %w", path, err)
+	}
+	return
+}
+
+// Export writes record in given format
+func Export(w io.Writer, record *Record, format string) error {
+	switch format {
+	case JSON:
+		return json.NewEncoder(w).Encode(record)
+	case CSV, HTML:
+		p := printer.ReportPrinter{
+			Out:    w,
+			Report: record.Report,
+		}
+		return p.Print(format)
+	case InfluxLine:
+		p := printer.ReportPrinter{
+			Out:    w,
+			Report: record.Report,
+		}
+		return p.Print("influx-summary")
+	case Prometheus:
+		return metrics.WriteAmp(w, record.Method, record.Namespace, record.Report)
+	default:
+		return fmt.Errorf("report: unsupported format %s", format)
+	}
+}
+
+// extension returns file extension of format
+func extension(format string) string {
+	switch format {
+	case InfluxLine:
+		return ".influx"
+	case Prometheus:
+		return ".prom"
+	default:
+		return "." + format
+	}
+}
+
+// writeFile creates file at path and writes it with fn
+func writeFile(path string, fn func(w io.Writer) error) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return
+	}
+	if err = fn(file); err != nil {
+		_ = file.Close()
+		return
+	}
+	return file.Close()
+}
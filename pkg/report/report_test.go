@@ -0,0 +1,101 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package report
+
+import (
+	"bytes"
+	"github.com/bojand/ghz/runner"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testRecord() *Record {
+	return &Record{
+		Method:    "mprpc.MongoProxy.Find",
+		Namespace: "sku",
+		Timestamp: time.Date(2020, 8, 1, 10, 0, 0, 0, time.UTC),
+		Config: RunConfig{
+			Turbo:        true,
+			ReadConcern:  "local",
+			WriteOptions: cfg.GetTurboWriteOptions(),
+			Amplify:      *cfg.MicroAmplifier(),
+		},
+		Report: &runner.Report{
+			Count:   20,
+			Average: 2 * time.Millisecond,
+			Rps:     1000,
+			LatencyDistribution: []runner.LatencyDistribution{
+				{Percentage: 50, Latency: time.Millisecond},
+				{Percentage: 99, Latency: 5 * time.Millisecond},
+			},
+			StatusCodeDist: map[string]int{"OK": 19, "Unavailable": 1},
+		},
+	}
+}
+
+// Test on saving and loading record
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := NewStore(dir, Prometheus)
+	path, err := store.Save(testRecord())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := filepath.Join(dir, "sku", "20200801T100000.000_mprpc.MongoProxy.Find.json")
+	if path != expected {
+		t.Errorf("expect %s, got %s", expected, path)
+	}
+	if _, err := os.Stat(strings.TrimSuffix(path, ".json") + ".prom"); err != nil {
+		t.Error(err)
+	}
+
+	record, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Method != "mprpc.MongoProxy.Find" || !record.Config.Turbo || record.Report.Count != 20 {
+		t.Errorf("unexpected record %+v", record)
+	}
+}
+
+// Test on prometheus export
+func TestExportPrometheus(t *testing.T) {
+	var b bytes.Buffer
+	if err := Export(&b, testRecord(), Prometheus); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, line := range []string{
+		`ebenchmark_amp_latency_seconds{method="mprpc.MongoProxy.Find",namespace="sku",quantile="0.99"} 0.005`,
+		`ebenchmark_amp_rps{method="mprpc.MongoProxy.Find",namespace="sku"} 1000`,
+		`ebenchmark_amp_requests{code="Unavailable",method="mprpc.MongoProxy.Find",namespace="sku"} 1`,
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("expect %s in output:\n%s", line, out)
+		}
+	}
+}
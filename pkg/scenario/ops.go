@@ -0,0 +1,304 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package scenario
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/xidongc/mongo_ebenchmark/model/order/orderpb"
+	"github.com/xidongc/mongo_ebenchmark/model/payment/paymentpb"
+	"github.com/xidongc/mongo_ebenchmark/model/product/productpb"
+	"github.com/xidongc/mongo_ebenchmark/model/sku/skupb"
+	"github.com/xidongc/mongo_ebenchmark/model/user/userpb"
+	"github.com/xidongc/mongo_ebenchmark/pkg/datagen"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// errSkip is returned by operation which has no data to work on yet,
+// eg: order.Get before any order created, it is not counted as error
+var errSkip = errors.New("skip")
+
+// clients of api services
+type clients struct {
+	sku     skupb.SkuServiceClient
+	product productpb.ProductServiceClient
+	user    userpb.UserServiceClient
+	order   orderpb.OrderServiceClient
+	payment paymentpb.PaymentServiceClient
+}
+
+func newClients(conn *grpc.ClientConn) *clients {
+	return &clients{
+		sku:     skupb.NewSkuServiceClient(conn),
+		product: productpb.NewProductServiceClient(conn),
+		user:    userpb.NewUserServiceClient(conn),
+		order:   orderpb.NewOrderServiceClient(conn),
+		payment: paymentpb.NewPaymentServiceClient(conn),
+	}
+}
+
+// dataset names documents by index so every virtual user picks from
+// the same data set, ids created during run are kept in pools
+type dataset struct {
+	Cardinality
+	provider paymentpb.PaymentProviderId
+	orders   *pool
+	charges  *pool
+}
+
+func newDataset(cardinality Cardinality, provider paymentpb.PaymentProviderId) *dataset {
+	return &dataset{
+		Cardinality: cardinality,
+		provider:    provider,
+		orders:      newPool(1024),
+		charges:     newPool(1024),
+	}
+}
+
+func (d *dataset) randomProduct(rng *rand.Rand) int {
+	return rng.Intn(d.Products)
+}
+
+func (d *dataset) randomSku(rng *rand.Rand) (product int, sku int) {
+	return d.randomProduct(rng), rng.Intn(d.SkusPerProduct)
+}
+
+func (d *dataset) randomUser(rng *rand.Rand) int {
+	return rng.Intn(d.Users)
+}
+
+// pool keeps latest ids up to size
+type pool struct {
+	lock sync.Mutex
+	ids  []string
+	next int
+}
+
+func newPool(size int) *pool {
+	return &pool{ids: make([]string, 0, size)}
+}
+
+func (p *pool) add(id string) {
+	if id == "" {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if len(p.ids) < cap(p.ids) {
+		p.ids = append(p.ids, id)
+		return
+	}
+	p.ids[p.next] = id
+	p.next = (p.next + 1) % len(p.ids)
+}
+
+func (p *pool) random(rng *rand.Rand) (string, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if len(p.ids) == 0 {
+		return "", false
+	}
+	return p.ids[rng.Intn(len(p.ids))], true
+}
+
+// operation sends one request to api server
+type operation func(ctx context.Context, c *clients, d *dataset, rng *rand.Rand) error
+
+// operations supported in scenario mix
+var operations = map[string]operation{
+	"sku.Get": func(ctx context.Context, c *clients, d *dataset, rng *rand.Rand) error {
+		_, err := c.sku.Get(ctx, &skupb.GetRequest{Name: datagen.SkuName(d.randomSku(rng))})
+		return err
+	},
+	"sku.GetProductSkus": func(ctx context.Context, c *clients, d *dataset, rng *rand.Rand) error {
+		_, err := c.sku.GetProductSkus(ctx, &skupb.GetProductSkusRequest{ProductId: datagen.ProductID(d.randomProduct(rng))})
+		return err
+	},
+	"sku.New": func(ctx context.Context, c *clients, d *dataset, rng *rand.Rand) error {
+		_, err := c.sku.New(ctx, skuRequest(d.randomSku(rng)))
+		return err
+	},
+	"product.Get": func(ctx context.Context, c *clients, d *dataset, rng *rand.Rand) error {
+		_, err := c.product.Get(ctx, &productpb.GetRequest{Id: datagen.ProductID(d.randomProduct(rng))})
+		return err
+	},
+	"product.New": func(ctx context.Context, c *clients, d *dataset, rng *rand.Rand) error {
+		_, err := c.product.New(ctx, productRequest(uuid.New().String()))
+		return err
+	},
+	"user.Get": func(ctx context.Context, c *clients, d *dataset, rng *rand.Rand) error {
+		_, err := c.user.Get(ctx, &userpb.GetRequest{Nickname: datagen.Nickname(d.randomUser(rng))})
+		return err
+	},
+	"user.New": func(ctx context.Context, c *clients, d *dataset, rng *rand.Rand) error {
+		_, err := c.user.New(ctx, userRequest(uuid.New().String()))
+		return err
+	},
+	"order.New": func(ctx context.Context, c *clients, d *dataset, rng *rand.Rand) error {
+		product, sku := d.randomSku(rng)
+		order, err := c.order.New(ctx, &orderpb.NewRequest{
+			Currency: paymentpb.Currency_USD,
+			Items: []*orderpb.Item{
+				{
+					ProductId: datagen.ProductID(product),
+					Name:      datagen.SkuName(product, sku),
+					Quantity:  int64(rng.Intn(3) + 1),
+					Amount:    datagen.SkuPrice(product, sku),
+					Currency:  paymentpb.Currency_USD,
+				},
+			},
+			Email: fmt.Sprintf("%s@ebenchmark.io", datagen.Nickname(d.randomUser(rng))),
+		})
+		if err == nil {
+			d.orders.add(order.GetId())
+		} else if status.Code(err) == codes.ResourceExhausted {
+			// sku is out of stock
+			return errSkip
+		}
+		return err
+	},
+	"order.Get": func(ctx context.Context, c *clients, d *dataset, rng *rand.Rand) error {
+		id, ok := d.orders.random(rng)
+		if !ok {
+			return errSkip
+		}
+		_, err := c.order.Get(ctx, &orderpb.GetRequest{Id: id})
+		return err
+	},
+	"order.Pay": func(ctx context.Context, c *clients, d *dataset, rng *rand.Rand) error {
+		id, ok := d.orders.random(rng)
+		if !ok {
+			return errSkip
+		}
+		_, err := c.order.Pay(ctx, &orderpb.PayRequest{
+			Id:                id,
+			Card:              testCard(),
+			PaymentProviderId: d.provider,
+		})
+		if status.Code(err) == codes.FailedPrecondition {
+			// order is paid already
+			return errSkip
+		}
+		return err
+	},
+	"payment.NewCharge": func(ctx context.Context, c *clients, d *dataset, rng *rand.Rand) error {
+		charge, err := c.payment.NewCharge(ctx, &paymentpb.ChargeRequest{
+			Currency:          paymentpb.Currency_USD,
+			Amount:            uint64(rng.Intn(10000) + 100),
+			Card:              testCard(),
+			UserId:            datagen.Nickname(d.randomUser(rng)),
+			PaymentProviderId: d.provider,
+		})
+		if err == nil {
+			d.charges.add(charge.GetId())
+		}
+		return err
+	},
+	"payment.Get": func(ctx context.Context, c *clients, d *dataset, rng *rand.Rand) error {
+		id, ok := d.charges.random(rng)
+		if !ok {
+			return errSkip
+		}
+		_, err := c.payment.Get(ctx, &paymentpb.GetRequest{Id: id})
+		return err
+	},
+}
+
+// OpNames returns names of all operations supported in mix
+func OpNames() (names []string) {
+	for name := range operations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// setup creates products with skus and users of dataset
+func setup(ctx context.Context, c *clients, d *dataset) error {
+	for p := 0; p < d.Products; p++ {
+		if _, err := c.product.New(ctx, productRequest(datagen.ProductID(p))); err != nil {
+			return fmt.Errorf("setup product %d: %w", p, err)
+		}
+		for s := 0; s < d.SkusPerProduct; s++ {
+			if _, err := c.sku.New(ctx, skuRequest(p, s)); err != nil {
+				return fmt.Errorf("setup sku %d of product %d: %w", s, p, err)
+			}
+		}
+	}
+	for u := 0; u < d.Users; u++ {
+		if _, err := c.user.New(ctx, userRequest(datagen.Nickname(u))); err != nil {
+			return fmt.Errorf("setup user %d: %w", u, err)
+		}
+	}
+	return nil
+}
+
+func productRequest(id string) *productpb.NewRequest {
+	return &productpb.NewRequest{
+		Id:        id,
+		Name:      id,
+		Active:    true,
+		Shippable: true,
+		Metadata:  map[string]string{"source": "scenario"},
+	}
+}
+
+func skuRequest(product int, sku int) *skupb.UpsertRequest {
+	return &skupb.UpsertRequest{
+		Name:      datagen.SkuName(product, sku),
+		Currency:  paymentpb.Currency_USD,
+		Active:    true,
+		ProductId: datagen.ProductID(product),
+		Price:     uint64(datagen.SkuPrice(product, sku)),
+		SkuLabel:  datagen.SkuName(product, sku),
+		Inventory: &skupb.Inventory{
+			SkuId:       datagen.SkuID(product, sku),
+			WarehouseId: 1,
+			Quantity:    1000,
+			Type:        skupb.Inventory_Finite,
+		},
+	}
+}
+
+func userRequest(nick string) *userpb.NewRequest {
+	return &userpb.NewRequest{
+		Name:     nick,
+		Nickname: nick,
+		Email:    fmt.Sprintf("%s@ebenchmark.io", nick),
+		Active:   true,
+		Currency: paymentpb.Currency_USD,
+	}
+}
+
+func testCard() *paymentpb.Card {
+	return &paymentpb.Card{
+		Number:      "4242424242424242",
+		ExpireMonth: "12",
+		ExpireYear:  "2030",
+		FirstName:   "e",
+		LastName:    "benchmark",
+		CVC:         "123",
+		Type:        paymentpb.CardType_Visa,
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package scenario
+
+import (
+	"context"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// rampInterval is how often runner adjusts virtual users to stage
+const rampInterval = 100 * time.Millisecond
+
+// Runner executes scenario against api server
+type Runner struct {
+	Scenario *Scenario
+	clients  *clients
+	data     *dataset
+	picker   *picker
+	stats    *Stats
+}
+
+// NewRunner creates runner on a connection to api server
+func NewRunner(scenario *Scenario, conn *grpc.ClientConn) *Runner {
+	return &Runner{
+		Scenario: scenario,
+		clients:  newClients(conn),
+		data:     newDataset(scenario.Cardinality, scenario.providerId()),
+		picker:   newPicker(scenario.Mix),
+		stats:    NewStats(),
+	}
+}
+
+// Run creates data set if Setup is specified, then runs virtual users
+// following stages until Duration is reached or ctx is done
+func (r *Runner) Run(ctx context.Context) (*Stats, error) {
+	if r.Scenario.Setup {
+		log.Infof("scenario %s: setup %+v", r.Scenario.Name, r.Scenario.Cardinality)
+		if err := setup(ctx, r.clients, r.data); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(r.Scenario.Duration))
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var users []context.CancelFunc
+	start := time.Now()
+	r.stats.start(start)
+
+	ticker := time.NewTicker(rampInterval)
+	defer ticker.Stop()
+	for {
+		target := r.Scenario.usersAt(time.Since(start))
+		for len(users) < target {
+			userCtx, stop := context.WithCancel(ctx)
+			users = append(users, stop)
+			wg.Add(1)
+			go func(seed int64) {
+				defer wg.Done()
+				r.user(userCtx, rand.New(rand.NewSource(seed)))
+			}(r.Scenario.Seed + int64(len(users)))
+		}
+		for len(users) > target {
+			users[len(users)-1]()
+			users = users[:len(users)-1]
+		}
+
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			r.stats.stop(time.Now())
+			return r.stats, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// user is a virtual user sending operations picked from mix until ctx done
+func (r *Runner) user(ctx context.Context, rng *rand.Rand) {
+	thinkTime := r.Scenario.ThinkTime
+	for ctx.Err() == nil {
+		name := r.picker.pick(rng)
+		begin := time.Now()
+		err := operations[name](ctx, r.clients, r.data, rng)
+		if ctx.Err() != nil {
+			return
+		}
+		r.stats.record(name, time.Since(begin), err)
+
+		pause := time.Duration(thinkTime.Min)
+		if spread := int64(thinkTime.Max - thinkTime.Min); spread > 0 {
+			pause += time.Duration(rng.Int63n(spread))
+		}
+		if pause > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(pause):
+			}
+		}
+	}
+}
+
+// picker picks operation by weight
+type picker struct {
+	names   []string
+	weights []int
+	total   int
+}
+
+func newPicker(mix []Op) *picker {
+	p := &picker{}
+	for _, op := range mix {
+		p.total += op.Weight
+		p.names = append(p.names, op.Op)
+		p.weights = append(p.weights, p.total)
+	}
+	return p
+}
+
+func (p *picker) pick(rng *rand.Rand) string {
+	n := rng.Intn(p.total)
+	for i, w := range p.weights {
+		if n < w {
+			return p.names[i]
+		}
+	}
+	return p.names[len(p.names)-1]
+}
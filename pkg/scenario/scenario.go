@@ -0,0 +1,221 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+// Package scenario runs declarative workloads against api server, a
+// scenario describes weighted operation mix across services, data
+// cardinality, think time and ramp-up stages in yaml or json:
+//
+//     name: browse-and-buy
+//     duration: 10m
+//     thinkTime: {min: 50ms, max: 200ms}
+//     cardinality: {products: 1000, skusPerProduct: 3, users: 500}
+//     stages:
+//       - {duration: 1m, users: 10}
+//       - {duration: 9m, users: 100}
+//     mix:
+//       - {op: sku.Get, weight: 60}
+//       - {op: product.Get, weight: 20}
+//       - {op: order.New, weight: 10}
+//       - {op: order.Pay, weight: 10}
+//
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/xidongc/mongo_ebenchmark/model/payment/paymentpb"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Duration is time.Duration read from string such as "1m30s"
+type Duration time.Duration
+
+// UnmarshalJSON parses duration string
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	return d.parse(s)
+}
+
+// UnmarshalYAML parses duration string
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	return d.parse(s)
+}
+
+func (d *Duration) parse(s string) error {
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// ThinkTime is pause of virtual user between two operations, picked
+// uniformly between Min and Max
+type ThinkTime struct {
+	Min Duration `json:"min" yaml:"min"`
+	Max Duration `json:"max" yaml:"max"`
+}
+
+// Cardinality is size of data set operations pick from
+type Cardinality struct {
+	Products       int `json:"products" yaml:"products"`
+	SkusPerProduct int `json:"skusPerProduct" yaml:"skusPerProduct"`
+	Users          int `json:"users" yaml:"users"`
+}
+
+// Stage ramps virtual users linearly from users of previous stage to
+// Users within Duration
+type Stage struct {
+	Duration Duration `json:"duration" yaml:"duration"`
+	Users    int      `json:"users" yaml:"users"`
+}
+
+// Op is an operation of mix, Op is named as <service>.<method>
+type Op struct {
+	Op     string `json:"op" yaml:"op"`
+	Weight int    `json:"weight" yaml:"weight"`
+}
+
+// Scenario describes a workload
+//
+// when Stages is empty, Users run constantly for Duration, otherwise
+// Duration defaults to sum of stages and last stage users are kept
+// until Duration is reached. Provider is name of payment provider
+// charges are made with, AliPay by default
+type Scenario struct {
+	Name        string      `json:"name" yaml:"name"`
+	Target      string      `json:"target" yaml:"target"`
+	Seed        int64       `json:"seed" yaml:"seed"`
+	Duration    Duration    `json:"duration" yaml:"duration"`
+	Users       int         `json:"users" yaml:"users"`
+	Setup       bool        `json:"setup" yaml:"setup"`
+	ThinkTime   ThinkTime   `json:"thinkTime" yaml:"thinkTime"`
+	Cardinality Cardinality `json:"cardinality" yaml:"cardinality"`
+	Provider    string      `json:"provider" yaml:"provider"`
+	Stages      []Stage     `json:"stages" yaml:"stages"`
+	Mix         []Op        `json:"mix" yaml:"mix"`
+}
+
+// Load reads scenario from yaml or json file based on extension
+func Load(path string) (scenario *Scenario, err error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return
+	}
+	scenario = &Scenario{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(b, scenario)
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalStrict(b, scenario)
+	default:
+		err = fmt.Errorf("unsupported scenario file %s, expect .yaml, .yml or .json", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scenario: load %s: %w", path, err)
+	}
+	if err = scenario.Validate(); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// Validate checks scenario and fills defaults
+func (s *Scenario) Validate() error {
+	if len(s.Mix) == 0 {
+		return fmt.Errorf("scenario %s: empty mix", s.Name)
+	}
+	for _, op := range s.Mix {
+		if _, ok := operations[op.Op]; !ok {
+			return fmt.Errorf("scenario %s: unknown op %s, expect one of %v", s.Name, op.Op, OpNames())
+		}
+		if op.Weight <= 0 {
+			return fmt.Errorf("scenario %s: weight of %s must be positive", s.Name, op.Op)
+		}
+	}
+	if s.ThinkTime.Max < s.ThinkTime.Min {
+		return fmt.Errorf("scenario %s: think time max is less than min", s.Name)
+	}
+
+	var total Duration
+	for _, stage := range s.Stages {
+		if stage.Duration <= 0 || stage.Users < 0 {
+			return fmt.Errorf("scenario %s: invalid stage %+v", s.Name, stage)
+		}
+		total += stage.Duration
+	}
+	if len(s.Stages) == 0 && s.Users <= 0 {
+		s.Users = 1
+	}
+	if s.Duration <= 0 {
+		s.Duration = total
+	}
+	if s.Duration <= 0 {
+		return fmt.Errorf("scenario %s: duration not specified", s.Name)
+	}
+
+	if s.Cardinality.Products <= 0 {
+		s.Cardinality.Products = 100
+	}
+	if s.Cardinality.SkusPerProduct <= 0 {
+		s.Cardinality.SkusPerProduct = 1
+	}
+	if s.Cardinality.Users <= 0 {
+		s.Cardinality.Users = 100
+	}
+
+	if s.Provider == "" {
+		s.Provider = paymentpb.PaymentProviderId_AliPay.String()
+	}
+	if _, ok := paymentpb.PaymentProviderId_value[s.Provider]; !ok {
+		return fmt.Errorf("scenario %s: unknown payment provider %s", s.Name, s.Provider)
+	}
+	return nil
+}
+
+// providerId returns id of payment provider
+func (s *Scenario) providerId() paymentpb.PaymentProviderId {
+	return paymentpb.PaymentProviderId(paymentpb.PaymentProviderId_value[s.Provider])
+}
+
+// usersAt returns virtual users expected at elapsed time since start
+func (s *Scenario) usersAt(elapsed time.Duration) int {
+	if len(s.Stages) == 0 {
+		return s.Users
+	}
+	from := 0
+	for _, stage := range s.Stages {
+		d := time.Duration(stage.Duration)
+		if elapsed < d {
+			return from + int(float64(stage.Users-from)*float64(elapsed)/float64(d))
+		}
+		elapsed -= d
+		from = stage.Users
+	}
+	return from
+}
@@ -0,0 +1,106 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package scenario
+
+import (
+	"github.com/xidongc/mongo_ebenchmark/model/payment/paymentpb"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// Test on loading example scenario files
+func TestLoad(t *testing.T) {
+	for _, path := range []string{
+		"../../scenarios/browse-and-buy.yaml",
+		"../../scenarios/checkout-spike.json",
+	} {
+		s, err := Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(s.Mix) != 4 || len(s.Stages) == 0 {
+			t.Errorf("unexpected scenario %+v", s)
+		}
+	}
+
+	s, err := Load("../../scenarios/checkout-spike.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Duration(s.Duration) != 2*time.Minute {
+		t.Errorf("expect duration sum of stages, got %s", time.Duration(s.Duration))
+	}
+	if time.Duration(s.ThinkTime.Max) != 50*time.Millisecond {
+		t.Errorf("expect think time max 50ms, got %s", time.Duration(s.ThinkTime.Max))
+	}
+}
+
+// Test on validating scenario
+func TestValidate(t *testing.T) {
+	s := &Scenario{Mix: []Op{{Op: "sku.Delete", Weight: 1}}, Users: 1, Duration: Duration(time.Second)}
+	if err := s.Validate(); err == nil {
+		t.Error("expect error on unknown op")
+	}
+	s = &Scenario{Mix: []Op{{Op: "sku.Get", Weight: 1}}}
+	if err := s.Validate(); err == nil {
+		t.Error("expect error on missing duration")
+	}
+	s = &Scenario{Mix: []Op{{Op: "order.Pay", Weight: 1}}, Duration: Duration(time.Second), Provider: "Stripe"}
+	if err := s.Validate(); err == nil {
+		t.Error("expect error on unknown payment provider")
+	}
+	s.Provider = "Mock"
+	if err := s.Validate(); err != nil || s.providerId() != paymentpb.PaymentProviderId_Mock {
+		t.Errorf("expect mock provider, got %v", err)
+	}
+}
+
+// Test on ramping users across stages
+func TestUsersAt(t *testing.T) {
+	s := &Scenario{
+		Stages: []Stage{
+			{Duration: Duration(10 * time.Second), Users: 100},
+			{Duration: Duration(10 * time.Second), Users: 50},
+		},
+	}
+	cases := map[time.Duration]int{
+		0:                0,
+		5 * time.Second:  50,
+		10 * time.Second: 100,
+		15 * time.Second: 75,
+		30 * time.Second: 50,
+	}
+	for elapsed, expected := range cases {
+		if users := s.usersAt(elapsed); users != expected {
+			t.Errorf("expect %d users at %s, got %d", expected, elapsed, users)
+		}
+	}
+}
+
+// Test on picking operations by weight
+func TestPicker(t *testing.T) {
+	p := newPicker([]Op{{Op: "sku.Get", Weight: 60}, {Op: "product.Get", Weight: 40}})
+	rng := rand.New(rand.NewSource(1))
+	counts := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		counts[p.pick(rng)]++
+	}
+	if counts["sku.Get"] < 5700 || counts["sku.Get"] > 6300 {
+		t.Errorf("expect about 60%% sku.Get, got %v", counts)
+	}
+}
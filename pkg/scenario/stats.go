@@ -0,0 +1,138 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package scenario
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Stats records latency and errors per operation
+type Stats struct {
+	lock      sync.Mutex
+	begin     time.Time
+	end       time.Time
+	latencies map[string][]time.Duration
+	errors    map[string]int
+	skipped   map[string]int
+}
+
+// NewStats creates empty stats
+func NewStats() *Stats {
+	return &Stats{
+		latencies: make(map[string][]time.Duration),
+		errors:    make(map[string]int),
+		skipped:   make(map[string]int),
+	}
+}
+
+func (s *Stats) start(t time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.begin = t
+}
+
+func (s *Stats) stop(t time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.end = t
+}
+
+func (s *Stats) record(op string, latency time.Duration, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	switch err {
+	case nil:
+	case errSkip:
+		s.skipped[op]++
+		return
+	default:
+		s.errors[op]++
+	}
+	s.latencies[op] = append(s.latencies[op], latency)
+}
+
+// OpSummary is result of an operation
+type OpSummary struct {
+	Op      string
+	Count   int
+	Errors  int
+	Skipped int
+	Rps     float64
+	P50     time.Duration
+	P90     time.Duration
+	P99     time.Duration
+}
+
+// Summary returns result per operation sorted by name
+func (s *Stats) Summary() (summaries []OpSummary) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	elapsed := s.end.Sub(s.begin).Seconds()
+	for op, latencies := range s.latencies {
+		sorted := append([]time.Duration(nil), latencies...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		summary := OpSummary{
+			Op:      op,
+			Count:   len(sorted),
+			Errors:  s.errors[op],
+			Skipped: s.skipped[op],
+			P50:     percentile(sorted, 50),
+			P90:     percentile(sorted, 90),
+			P99:     percentile(sorted, 99),
+		}
+		if elapsed > 0 {
+			summary.Rps = float64(summary.Count) / elapsed
+		}
+		summaries = append(summaries, summary)
+	}
+	for op, skipped := range s.skipped {
+		if _, ok := s.latencies[op]; !ok {
+			summaries = append(summaries, OpSummary{Op: op, Skipped: skipped})
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Op < summaries[j].Op })
+	return
+}
+
+// Print writes summary as table
+func (s *Stats) Print(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "OP\tCOUNT\tERRORS\tSKIPPED\tRPS\tP50\tP90\tP99")
+	for _, o := range s.Summary() {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.2f\t%s\t%s\t%s\n",
+			o.Op, o.Count, o.Errors, o.Skipped, o.Rps, o.P50, o.P90, o.P99)
+	}
+	return w.Flush()
+}
+
+// percentile returns latency at percentage of sorted latencies
+func percentile(sorted []time.Duration, percentage int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := (len(sorted)*percentage+99)/100 - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
@@ -0,0 +1,105 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+// Package tracing traces a request from api server handler through proxy
+// rpcs with opentelemetry. spans are propagated in process by context, and
+// to proxy and from gateway by w3c traceparent in grpc metadata. ended spans
+// are batched and exported to otlp/http collectors or a local json lines file
+package tracing
+
+import (
+	"context"
+	"errors"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/status"
+)
+
+// Service name of spans
+const Service = "mongo_ebenchmark"
+
+// Span kinds
+const (
+	Internal = trace.SpanKindInternal
+	Server   = trace.SpanKindServer
+	Client   = trace.SpanKindClient
+)
+
+// New creates tracer provider exporting to otlp endpoint and file in
+// config, nil if neither is specified. Shutdown must be called to flush
+// spans
+func New(config *cfg.TraceConfig) (*sdktrace.TracerProvider, error) {
+	if config.TraceSample < 0 || config.TraceSample > 1 {
+		return nil, errors.New("trace sample ratio is not between 0 and 1")
+	}
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.TraceSample))),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", Service))),
+	}
+	var exporters int
+	if config.TraceOTLP != "" {
+		exporter, err := newOTLPExporter(config.TraceOTLP, config.TraceHeaders)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+		exporters++
+	}
+	if config.TraceFile != "" {
+		exporter, err := newFileExporter(config.TraceFile)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+		exporters++
+	}
+	if exporters == 0 {
+		return nil, nil
+	}
+	return sdktrace.NewTracerProvider(options...), nil
+}
+
+// SetProvider sets provider used by Start and grpc interceptors, and w3c
+// trace context propagation
+func SetProvider(provider trace.TracerProvider) {
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+}
+
+// Start starts a span as child of span in ctx, or of remote span extracted
+// from grpc metadata, span records nothing if tracing is disabled, eg:
+//
+//     ctx, span := tracing.Start(ctx, "sku.Service.GetProductSkus", tracing.Internal)
+//     defer func() { tracing.End(span, err) }()
+//
+func Start(ctx context.Context, name string, kind trace.SpanKind) (context.Context, trace.Span) {
+	return otel.Tracer(Service).Start(ctx, name, trace.WithSpanKind(kind))
+}
+
+// End ends span with grpc code and message of err
+func End(span trace.Span, err error) {
+	if err != nil {
+		rpcStatus := status.Convert(err)
+		span.SetStatus(codes.Error, rpcStatus.Code().String()+": "+rpcStatus.Message())
+	}
+	span.End()
+}
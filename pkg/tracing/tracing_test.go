@@ -0,0 +1,154 @@
+/*
+ * mongodb_ebenchmark - Mongodb grpc proxy benchmark for e-commerce workload (still in dev)
+ * Copyright (c) 2020 - Chen, Xidong <chenxidong2009@hotmail.com>
+ *
+ * All rights reserved.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ *
+ */
+
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"github.com/xidongc/mongo_ebenchmark/pkg/cfg"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+// Test on span tree from server handler to proxy call
+func TestTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	SetProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	const traceId, callerId = "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"
+	incoming := metadata.NewIncomingContext(context.Background(), metadata.Pairs(TraceparentKey, "00-"+traceId+"-"+callerId+"-01"))
+
+	var outgoing metadata.MD
+	info := &grpc.UnaryServerInfo{FullMethod: "/productpb.ProductService/Get"}
+	_, err := otelgrpc.UnaryServerInterceptor()(incoming, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		ctx, span := Start(ctx, "mprpc.MongoProxy.Find", Client)
+		outgoing, _ = metadata.FromOutgoingContext(Inject(metadata.AppendToOutgoingContext(ctx, "x-request-id", "1")))
+		End(span, nil)
+		return nil, status.Error(codes.NotFound, "product not found")
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expect handler error returned, got %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expect 2 spans, got %d", len(spans))
+	}
+	find, get := spans[0], spans[1]
+	if get.Name() != "productpb.ProductService/Get" || get.SpanKind() != Server || get.Status().Code != otelcodes.Error ||
+		get.SpanContext().TraceID().String() != traceId || get.Parent().SpanID().String() != callerId {
+		t.Errorf("unexpected server span %+v", get)
+	}
+	if find.SpanKind() != Client || find.Status().Code != otelcodes.Unset ||
+		find.SpanContext().TraceID() != get.SpanContext().TraceID() || find.Parent().SpanID() != get.SpanContext().SpanID() {
+		t.Errorf("unexpected client span %+v", find)
+	}
+	expected := "00-" + traceId + "-" + find.SpanContext().SpanID().String() + "-01"
+	if values := outgoing.Get(TraceparentKey); len(values) != 1 || values[0] != expected || outgoing.Get("x-request-id")[0] != "1" {
+		t.Errorf("expect traceparent %s sent to proxy, got %v", expected, outgoing)
+	}
+}
+
+// Test on file and otlp exporters
+func TestExporters(t *testing.T) {
+	var requests int32
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = ioutil.ReadAll(r.Body)
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Authorization") != "token" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer collector.Close()
+
+	dir, err := ioutil.TempDir("", "tracing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "traces.json")
+
+	provider, err := New(&cfg.TraceConfig{
+		TraceOTLP:    collector.URL,
+		TraceHeaders: map[string]string{"Authorization": "token"},
+		TraceFile:    path,
+		TraceSample:  1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracer := provider.Tracer(Service)
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	_, child := tracer.Start(ctx, "child", trace.WithSpanKind(Client))
+	End(child, status.Error(codes.Unavailable, "proxy down"))
+	End(parent, nil)
+	if err = provider.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	type record struct {
+		Name   string
+		Parent struct{ SpanID string }
+		Status struct{ Description string }
+	}
+	var records []record
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var r record
+		if err = json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, r)
+	}
+	if len(records) != 2 || records[0].Name != "child" || records[0].Status.Description != "Unavailable: proxy down" ||
+		records[0].Parent.SpanID != parent.SpanContext().SpanID().String() {
+		t.Errorf("unexpected spans in file %+v", records)
+	}
+	if atomic.LoadInt32(&requests) != 1 {
+		t.Errorf("expect spans posted to collector once, got %d", requests)
+	}
+
+	if provider, err = New(&cfg.TraceConfig{TraceSample: 1}); provider != nil || err != nil {
+		t.Error("expect tracing disabled without exporter")
+	}
+	if _, err = New(&cfg.TraceConfig{TraceFile: path, TraceSample: 2}); err == nil {
+		t.Error("expect invalid sample ratio")
+	}
+}